@@ -0,0 +1,51 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAll(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push("a", "b", "c")
+
+	items := []interface{}{}
+	list.All()(func(item interface{}) bool {
+		items = append(items, item)
+		return true
+	})
+	require.Equal(t, []interface{}{"a", "b", "c"}, items)
+
+	// Stops once yield returns false
+	items = []interface{}{}
+	list.All()(func(item interface{}) bool {
+		items = append(items, item)
+		return len(items) < 2
+	})
+	require.Equal(t, []interface{}{"a", "b"}, items)
+}
+
+func TestAll2(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push("a", "b", "c", "d")
+
+	// Iterates over a snapshot, changes after creating the iterator are not reflected
+	all := list.All2()
+	list.Push("e")
+
+	pairs := map[int]interface{}{}
+	all(func(index int, item interface{}) bool {
+		pairs[index] = item
+		return true
+	})
+	require.Equal(t, map[int]interface{}{0: "a", 1: "b", 2: "c", 3: "d"}, pairs)
+
+	// Stops once yield returns false
+	pairs = map[int]interface{}{}
+	list.All2()(func(index int, item interface{}) bool {
+		pairs[index] = item
+		return index < 1
+	})
+	require.Equal(t, map[int]interface{}{0: "a", 1: "b"}, pairs)
+}
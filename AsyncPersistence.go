@@ -0,0 +1,99 @@
+package concurrentList
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// persistOperation is a single pending operation for the persistence worker
+type persistOperation struct {
+	item   interface{}
+	delete bool
+
+	// Path of the file of the item and whether it needs to be written or deleted (see persistenceOperation)
+	itemPath string
+	file     bool
+
+	// Written along with the item (see persistedSequence)
+	sequence uint64
+}
+
+// persistenceWorker performs all pending persistence operations in the order they were enqueued,
+// so the file of an item is never deleted before it was written
+func (l *ConcurrentList) persistenceWorker(persistQueue <-chan persistOperation) {
+	defer close(l.persistDone)
+
+	for op := range persistQueue {
+		l.persistenceApply(op)
+
+		// Operations are dropped while the list is locked, they are reported here so the errorHandler may use the list
+		if dropped := atomic.SwapInt64(l.persistDropped, 0); dropped > 0 {
+			l.handleError(fmt.Errorf("%w: %d operations were dropped", ErrPersistenceQueueFull, dropped))
+		}
+
+		l.persistProgress.L.Lock()
+		l.persistPerformed++
+		l.persistProgress.Broadcast()
+		l.persistProgress.L.Unlock()
+	}
+}
+
+// internal helper function for handing an operation to the persistence worker. Never blocks: if the queue is full, the
+// operation is dropped and the worker passes ErrPersistenceQueueFull to the errorHandler. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) persistenceEnqueue(op persistOperation) {
+	select {
+	case l.persistQueue <- op:
+		l.persistEnqueued++
+	default:
+		atomic.StoreInt64(l.persistFailing, 1)
+		atomic.AddInt64(l.persistDropped, 1)
+	}
+}
+
+// internal helper function for waiting until the worker performed the first target operations which were enqueued
+func (l *ConcurrentList) waitPersisted(target uint64) {
+	l.persistProgress.L.Lock()
+	defer l.persistProgress.L.Unlock()
+
+	for l.persistPerformed < target {
+		l.persistProgress.Wait()
+	}
+}
+
+// internal helper function for checking if any enqueued operation is not performed yet. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) persistencePending() bool {
+	l.persistProgress.L.Lock()
+	defer l.persistProgress.L.Unlock()
+
+	return l.persistPerformed < l.persistEnqueued
+}
+
+// FlushPersistence blocks until all persistence operations which are pending at the time of calling are done, including
+// the one which exceeded WithPersistenceTimeout. Returns immediately if neither WithAsyncPersistence nor WithPersistenceTimeout is
+// used and ErrPersistenceDisabled if WithPersistence is not used
+func (l *ConcurrentList) FlushPersistence() error {
+	if !l.opts.persistChanges {
+		return ErrPersistenceDisabled
+	}
+
+	l.lock.Lock()
+	target := l.persistEnqueued
+	l.lock.Unlock()
+
+	l.waitPersisted(target)
+	l.waitPersistenceTimeouts()
+	return nil
+}
+
+// internal helper function for waiting until no persistence operation is pending (e.g. before rewriting the directory).
+// the caller needs to make sure the collection is locked. The lock is released while waiting, so the worker is not blocked by
+// an errorHandler which uses the list. Once flushPersistenceLocked returns, the lock is held again and nothing is pending
+func (l *ConcurrentList) flushPersistenceLocked() {
+	for l.persistencePending() || l.persistenceTimedOut() {
+		target := l.persistEnqueued
+		l.lock.Unlock()
+		l.waitPersisted(target)
+		l.waitPersistenceTimeouts()
+		l.lock.Lock()
+	}
+}
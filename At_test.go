@@ -0,0 +1,33 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAt(t *testing.T) {
+	list := NewConcurrentList()
+	_, err := list.At(0)
+	require.Equal(t, ErrIndexOutOfRange, err)
+
+	list.Push("a", "b", "c")
+	for index, expected := range []string{"a", "b", "c"} {
+		item, err := list.At(index)
+		require.NoError(t, err)
+		require.Equal(t, expected, item)
+	}
+	require.Equal(t, 3, list.Length())
+
+	_, err = list.At(-1)
+	require.Equal(t, ErrIndexOutOfRange, err)
+	_, err = list.At(3)
+	require.Equal(t, ErrIndexOutOfRange, err)
+
+	// Positions follow the sort order
+	sorted := NewOrderedList()
+	sorted.Push(3, 1, 2)
+	item, err := sorted.At(1)
+	require.NoError(t, err)
+	require.Equal(t, 2, item)
+}
@@ -0,0 +1,41 @@
+package concurrentList
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckInvariants(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestCheckInvariants")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithSorting(func(i, j interface{}) bool {
+		return i.(int) < j.(int)
+	}), WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	list.Push(3, 1, 2)
+	require.NoError(t, list.checkInvariants())
+
+	// Unsorted items
+	list.data[0], list.data[1] = list.data[1], list.data[0]
+	require.EqualError(t, list.checkInvariants(), "item 1 (1) is sorted behind item 0 (2)")
+	list.data[0], list.data[1] = list.data[1], list.data[0]
+
+	// Length counter out of sync
+	atomic.StoreInt64(list.length, 5)
+	require.EqualError(t, list.checkInvariants(), "length counter is 5, but the list holds 3 items")
+	atomic.StoreInt64(list.length, 3)
+
+	// Missing file
+	require.NoError(t, os.Remove(filepath.Join(tempDir, "2")))
+	require.EqualError(t, list.checkInvariants(), "2 files are persisted, but 3 are expected for 3 items")
+}
@@ -0,0 +1,52 @@
+package concurrentList
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker suppresses persistence operations for a while after too many consecutive failures (see WithPersistenceCircuitBreaker)
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	onOpen    func()
+
+	// Consecutive failures since the last success or since the breaker was opened
+	failures int
+
+	// Operations are suppressed until then
+	openUntil time.Time
+
+	// Protect failures and openUntil (the persistence worker and synchronous persistence may overlap while closing)
+	lock sync.Mutex
+}
+
+// allow reports if an operation may be attempted, i.e. if the breaker is not open
+func (b *circuitBreaker) allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return !time.Now().Before(b.openUntil)
+}
+
+// record keeps track of the result of an attempted operation and opens the breaker once threshold consecutive operations failed
+func (b *circuitBreaker) record(err error) {
+	b.lock.Lock()
+	if err == nil {
+		b.failures = 0
+		b.lock.Unlock()
+		return
+	}
+
+	b.failures++
+	opened := b.failures >= b.threshold
+	if opened {
+		b.failures = 0
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+	b.lock.Unlock()
+
+	if opened && b.onOpen != nil {
+		b.onOpen()
+	}
+}
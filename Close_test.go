@@ -0,0 +1,91 @@
+package concurrentList
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClose(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestClose")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}), WithAsyncPersistence(100), WithAutoTTL(time.Hour, time.Millisecond))
+
+	list.Push(1, 2)
+	require.NoError(t, list.Close())
+	require.Equal(t, ErrClosed, list.Close())
+
+	// Pending persistence operations are done
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	// Remaining items can still be consumed, but waiting for more is not possible
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	item, err := list.GetNext(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, item)
+	item, err = list.Shift()
+	require.NoError(t, err)
+	require.Equal(t, 2, item)
+	_, err = list.GetNext(ctx)
+	require.Equal(t, ErrClosed, err)
+
+	files, err = ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 0)
+}
+
+func TestCloseWakesWaiting(t *testing.T) {
+	list := NewConcurrentList()
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := list.GetNext(context.Background())
+		errs <- err
+	}()
+	go func() {
+		_, err := list.GetNextAcceptable(context.Background(), func(item interface{}) (bool, bool) {
+			return false, false
+		})
+		errs <- err
+	}()
+
+	// Make sure both are waiting
+	for running, _ := list.debug(); running != 1; running, _ = list.debug() {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, list.Close())
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			require.Equal(t, ErrClosed, err)
+		case <-time.After(time.Second):
+			require.FailNow(t, "waiting calls were not woken up by Close")
+		}
+	}
+}
+
+func TestDrain(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push(1, 2, 3)
+
+	require.Equal(t, []interface{}{1, 2, 3}, list.Drain())
+	require.Equal(t, 0, list.Length())
+	require.Equal(t, []interface{}{}, list.Drain())
+}
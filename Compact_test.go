@@ -0,0 +1,59 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompact(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestCompact")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	discarded := []interface{}{}
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}), WithOnDiscard(func(item interface{}) {
+		discarded = append(discarded, item)
+	}))
+	list.Push(3, -1, 0, 5, -2)
+
+	// Remove negatives and double positives
+	removed := list.Compact(func(item interface{}) (bool, interface{}) {
+		if item.(int) < 0 {
+			return false, nil
+		}
+		return true, item.(int) * 2
+	})
+	require.Equal(t, 2, removed)
+	require.Equal(t, []interface{}{-1, -2}, discarded)
+	require.Equal(t, []interface{}{6, 0, 10}, list.snapshot())
+
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	names := []string{}
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	sort.Strings(names)
+	require.Equal(t, []string{"0", "10", "6"}, names)
+	require.NoError(t, list.checkInvariants())
+	require.Empty(t, list.Errors())
+
+	// Replacements are sorted
+	sorted := NewConcurrentList(WithSorting(func(i, j interface{}) bool {
+		return i.(int) < j.(int)
+	}))
+	sorted.Push(1, 2, 3)
+	require.Equal(t, 0, sorted.Compact(func(item interface{}) (bool, interface{}) {
+		return true, -item.(int)
+	}))
+	require.Equal(t, []interface{}{-3, -2, -1}, sorted.snapshot())
+}
@@ -0,0 +1,64 @@
+package concurrentList
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareAndShift(t *testing.T) {
+	equal := func(a, b interface{}) bool {
+		return a == b
+	}
+
+	list := NewConcurrentList()
+	_, ok := list.CompareAndShift(1, equal)
+	require.False(t, ok)
+
+	list.Push(1, 2)
+	_, ok = list.CompareAndShift(2, equal)
+	require.False(t, ok)
+	require.Equal(t, 2, list.Length())
+
+	item, ok := list.CompareAndShift(1, equal)
+	require.True(t, ok)
+	require.Equal(t, 1, item)
+	require.Equal(t, 1, list.Length())
+}
+
+func TestCompareAndShiftConcurrent(t *testing.T) {
+	equal := func(a, b interface{}) bool {
+		return a == b
+	}
+
+	for i := 0; i < 100; i++ {
+		list := NewConcurrentList()
+		list.Push("head", "next")
+
+		// Both goroutines peek the same head, but only one of them may consume it
+		peeked := sync.WaitGroup{}
+		peeked.Add(2)
+		done := sync.WaitGroup{}
+		done.Add(2)
+		succeeded := int32(0)
+		for j := 0; j < 2; j++ {
+			go func() {
+				defer done.Done()
+				head, err := list.Peek()
+				require.NoError(t, err)
+				peeked.Done()
+				peeked.Wait()
+
+				if _, ok := list.CompareAndShift(head, equal); ok {
+					atomic.AddInt32(&succeeded, 1)
+				}
+			}()
+		}
+		done.Wait()
+
+		require.Equal(t, int32(1), succeeded)
+		require.Equal(t, []interface{}{"next"}, list.TopK(2))
+	}
+}
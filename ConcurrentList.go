@@ -1,9 +1,12 @@
 package concurrentList
 
 import (
+	"bytes"
 	"context"
+	"encoding"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -14,8 +17,32 @@ import (
 	"time"
 )
 
-// ErrEmptyList is returned if one tries to get items from an empty list
-var ErrEmptyList = errors.New("list is empty")
+var (
+	// ErrEmptyList is returned if one tries to get items from an empty list
+	ErrEmptyList = errors.New("list is empty")
+	// ErrIndexOutOfRange is returned if one tries to access a position which does not exist in the list
+	ErrIndexOutOfRange = errors.New("index out of range")
+	// ErrClosed is returned if one tries to wait for items of a closed list or closes it twice
+	ErrClosed = errors.New("list is closed")
+	// ErrSortingEnabled is returned if one tries to position items manually in a list which uses WithSorting
+	ErrSortingEnabled = errors.New("list is sorted")
+	// ErrListFull is returned if one tries to add items to a list which is at its capacity (see WithCapacity)
+	ErrListFull = errors.New("list is full")
+	// ErrPriorityDisabled is returned if one tries to push with an explicit priority into a list which was not created by NewPriorityList or NewMaxPriorityList
+	ErrPriorityDisabled = errors.New("priority is disabled")
+	// ErrDuplicateItem is returned if multiple persisted files hold the same item and WithLoadDuplicatePolicy(DuplicatesError) is used
+	ErrDuplicateItem = errors.New("duplicate item")
+	// ErrPersistenceDisabled is returned if one tries to use persistence features of a list without WithPersistence
+	ErrPersistenceDisabled = errors.New("persistence is disabled")
+	// ErrPersistenceTimeout is passed to the errorHandler if a persistence operation takes longer than WithPersistenceTimeout (or is skipped because a previous one did)
+	ErrPersistenceTimeout = errors.New("persistence timed out")
+	// ErrPersistenceQueueFull is passed to the errorHandler if a persistence operation is dropped because the queue of WithAsyncPersistence is full
+	ErrPersistenceQueueFull = errors.New("persistence queue is full")
+	// ErrVersionConflict is passed to the errorHandler if the file of an item was written by another list in the meantime (see WithVersioning)
+	ErrVersionConflict = errors.New("version conflict")
+	// ErrInvalidArgument is returned if one passes arguments which cannot be satisfied (e.g. a minimum which is larger than the maximum)
+	ErrInvalidArgument = errors.New("invalid argument")
+)
 
 // ConcurrentList is a thread-safe datastructure which holds a list of items (interfaces{})
 // if desired these items can be automatically sorted or the list persisted on the HDD upon each change
@@ -23,26 +50,170 @@ var ErrEmptyList = errors.New("list is empty")
 // to continue in the same order GetNext() is called) or a passed context expires
 type ConcurrentList struct {
 	// Hold data
-	data []interface{}
+	data []*listItem
 
 	// Protect list
 	lock *sync.Mutex
 
-	// Condition for waiting reads
-	notEmpty *sync.Cond
+	// Waiting reads in the order they started waiting
+	waiters []*waiter
+
+	// Waiting reads which were woken up, but did not reacquire the lock yet
+	wokenWaiters int
+
+	// Waiters which are done waiting and can be reused (see WithFastPath)
+	idleWaiters []*waiter
+
+	// Closed when items are added or removed (only exists while someone is waiting for a change)
+	changed chan struct{}
 
 	// Options
 	opts concurrentListOptions
 
+	// Receives whenever the list goes from empty to non-empty
+	nonEmpty chan struct{}
+
+	// Pending persistence operations (see WithAsyncPersistence)
+	persistQueue chan persistOperation
+	persistDone  chan struct{}
+
+	// Number of operations which were enqueued (guarded by lock) and performed by the worker (guarded by persistProgress)
+	persistEnqueued  uint64
+	persistPerformed uint64
+	persistProgress  *sync.Cond
+
+	// Number of operations which were dropped because the queue was full and are not reported yet
+	persistDropped *int64
+
+	// Number of items sharing the same file (see WithContentHashPersistence)
+	persistRefs map[string]int
+
+	// Version of the record in every file which was written or read (see WithVersioning). Persistence operations
+	// run outside of the lock of the list (see WithAsyncPersistence and WithPersistenceTimeout), so it has its own
+	persistVersions     map[string]uint64
+	persistVersionsLock *sync.Mutex
+
+	// Suppresses persistence operations after repeated failures (see WithPersistenceCircuitBreaker)
+	persistBreaker *circuitBreaker
+
+	// Whether persistence operations are skipped (see SuspendPersistence)
+	persistSuspended bool
+
+	// Closed once the persistence operation which exceeded WithPersistenceTimeout is done
+	persistTimeoutPending chan struct{}
+	persistTimeoutLock    *sync.Mutex
+
+	// Sequence of the item which was added last
+	sequence uint64
+
+	// Number of GetNext and GetNextOrHighWater calls per bucket of waitTimeBuckets (see WaitTimeHistogram)
+	waitTimes []int64
+
+	// Number of items which were not reconstructed from their file yet (see WithLazyPersistenceLoad)
+	lazyPending int
+
+	// Registrations of WatermarkEvents
+	watermarks []*watermark
+
+	// Credit of every category of GetNextWeighted
+	weightedCredits map[string]int
+
+	// Whether pushed items still need to be sorted and the timer which sorts them (see WithDeferredSort)
+	sortPending bool
+	sortTimer   *time.Timer
+
+	// Closed once Close() is called
+	closed bool
+	done   chan struct{}
+
+	// Length of data, can be read without acquiring the lock
+	length *int64
+
+	// 1 if the last persistence operation failed (see PersistenceHealthy)
+	persistFailing *int64
+
+	// When items were last added to and taken from the head of the list (see Diagnostics)
+	lastPushedAt  time.Time
+	lastShiftedAt time.Time
+
+	// Statistics
+	totalPushed   *int64
+	totalShifted  *int64
+	totalConsumed *int64
+	ttlEvictions  *int64
+
+	// Collected errors (if no errorHandler is set)
+	errors     []error
+	errorsLock *sync.Mutex
+
 	// debug
-	runningSignalRoutines *int64
-	runningWaitRoutines   *int64
+	runningWaitRoutines *int64
+	wakeUps             *int64
+	sorts               *int64
+}
+
+// listItem holds a single item of the list along with internal bookkeeping
+type listItem struct {
+	value interface{}
+
+	// When the item was added to the list
+	pushedAt time.Time
+
+	// Overrides the priority of the value (see PushWithPriority)
+	priority *int
+
+	// Increasing number of when the item was added to the list, keeps equal items of a sorted list in order
+	sequence uint64
+
+	// Path of the file the value still needs to be reconstructed from (see WithLazyPersistenceLoad)
+	lazyPath string
+
+	// Size of the file of the item (only known with WithMaxPersistedBytes)
+	size int64
+
+	// Metadata which was pushed along with the value (see PushEntry)
+	metadata map[string]interface{}
+
+	// Whether a handler of ProcessNext is currently processing the item
+	claimed bool
+
+	// Number of failed attempts of ProcessNext and until when the item is skipped by ProcessNext (see WithRetryPolicy)
+	attempts  int
+	notBefore time.Time
 }
 
 // Constructor for creating a ConcurrentList (is required for initializing subscriber channels)
+// Errors which occur while setting up persistence are passed to the errorHandler of WithPersistence (if any)
 func NewConcurrentList(opts ...ConcurrentListOption) *ConcurrentList {
+	list := newConcurrentList(opts...)
+
+	err := list.persistenceInit()
+	if err != nil {
+		list.handleError(err)
+	}
+
+	list.start()
+	return list
+}
+
+// NewConcurrentListChecked creates a ConcurrentList just like NewConcurrentList, but returns an error
+// if the persistence directory cannot be created or the persisted list cannot be loaded
+func NewConcurrentListChecked(opts ...ConcurrentListOption) (*ConcurrentList, error) {
+	list := newConcurrentList(opts...)
+
+	err := list.persistenceInit()
+	if err != nil {
+		return nil, err
+	}
+
+	list.start()
+	return list, nil
+}
+
+func newConcurrentList(opts ...ConcurrentListOption) *ConcurrentList {
 	mergedOpts := concurrentListOptions{
-		lessFunc: nil,
+		lessFunc:        nil,
+		errorBufferSize: defaultErrorBufferSize,
 	}
 	for _, opt := range opts {
 		opt.apply(&mergedOpts)
@@ -50,65 +221,396 @@ func NewConcurrentList(opts ...ConcurrentListOption) *ConcurrentList {
 
 	lock := new(sync.Mutex)
 
-	runningSignalRoutines := int64(0)
+	length := int64(0)
+	persistFailing := int64(0)
+	totalPushed := int64(0)
+	totalShifted := int64(0)
+	totalConsumed := int64(0)
+	ttlEvictions := int64(0)
 	runningWaitRoutines := int64(0)
+	wakeUps := int64(0)
+	sorts := int64(0)
 
 	list := &ConcurrentList{
-		data:                  []interface{}{},
-		lock:                  lock,
-		notEmpty:              sync.NewCond(lock),
-		opts:                  mergedOpts,
-		runningSignalRoutines: &runningSignalRoutines,
-		runningWaitRoutines:   &runningWaitRoutines,
+		data:                []*listItem{},
+		lock:                lock,
+		waiters:             []*waiter{},
+		opts:                mergedOpts,
+		nonEmpty:            make(chan struct{}, 1),
+		done:                make(chan struct{}),
+		length:              &length,
+		persistFailing:      &persistFailing,
+		totalPushed:         &totalPushed,
+		totalShifted:        &totalShifted,
+		totalConsumed:       &totalConsumed,
+		ttlEvictions:        &ttlEvictions,
+		errors:              []error{},
+		errorsLock:          new(sync.Mutex),
+		runningWaitRoutines: &runningWaitRoutines,
+		wakeUps:             &wakeUps,
+		sorts:               &sorts,
+		waitTimes:           make([]int64, len(waitTimeBuckets)),
+		persistTimeoutLock:  new(sync.Mutex),
+		persistProgress:     sync.NewCond(new(sync.Mutex)),
+		persistDropped:      new(int64),
 	}
 
-	// Reconstruct persisted list
-	if mergedOpts.persistChanges {
-		err := list.persistenceLoad()
-		if err != nil && mergedOpts.persistErrorHandler != nil {
-			(*mergedOpts.persistErrorHandler)(err)
+	if mergedOpts.persistContentHash {
+		list.persistRefs = map[string]int{}
+	}
+	if mergedOpts.persistVersioning {
+		list.persistVersions = map[string]uint64{}
+		list.persistVersionsLock = new(sync.Mutex)
+	}
+	if mergedOpts.persistBreakerThreshold > 0 {
+		list.persistBreaker = &circuitBreaker{
+			threshold: mergedOpts.persistBreakerThreshold,
+			cooldown:  mergedOpts.persistBreakerCooldown,
+			onOpen:    mergedOpts.persistBreakerOnOpen,
 		}
 	}
 
-	if mergedOpts.ttlEnabled {
+	return list
+}
+
+// start all background routines of the list
+func (l *ConcurrentList) start() {
+	if l.opts.persistChanges && l.opts.persistAsync {
+		l.persistQueue = make(chan persistOperation, l.opts.persistQueueSize)
+		l.persistDone = make(chan struct{})
+		go l.persistenceWorker(l.persistQueue)
+	}
+
+	if l.opts.ttlEnabled {
 		go func() {
 			for {
-				list.DeleteWithFilter(func(item interface{}) bool {
-					ttlAttribute := (*mergedOpts.ttlFunc)(item)
-					return time.Since(ttlAttribute) > *mergedOpts.ttlDuration
-				})
-				time.Sleep(*mergedOpts.ttlCheckInverval)
+				l.lock.Lock()
+				l.sweepExpired()
+
+				// An empty list is not checked again before something changes. Otherwise the next check happens once
+				// the next item expires, but at least every ttlCheckInterval
+				var changed chan struct{}
+				var checkAgain <-chan time.Time
+				if len(l.data) == 0 {
+					if l.changed == nil {
+						l.changed = make(chan struct{})
+					}
+					changed = l.changed
+				} else {
+					wait := *l.opts.ttlCheckInverval
+					if next, ok := l.nextExpiry(); ok && time.Until(next) < wait {
+						wait = time.Until(next)
+					}
+					checkAgain = time.After(wait)
+				}
+				l.lock.Unlock()
+
+				select {
+				case <-checkAgain:
+				case <-changed:
+				case <-l.done:
+					return
+				}
 			}
 		}()
 	}
+}
 
-	return list
+// Close stops all background routines of the list (e.g. WithTTL) and waits until all pending
+// persistence operations are done (see WithAsyncPersistence). Returns ErrClosed if the list is already closed.
+// Items which are still in the list are not abandoned: they can be consumed after closing (or taken all at once with Drain),
+// but calls which would need to wait for an item (e.g. GetNext on an empty list) return ErrClosed instead.
+// Calls which are already waiting when the list is closed are woken up and return ErrClosed as well
+func (l *ConcurrentList) Close() error {
+	l.lock.Lock()
+	if l.closed {
+		l.lock.Unlock()
+		return ErrClosed
+	}
+	l.closed = true
+	close(l.done)
+
+	// Nobody is going to push for waiting consumers anymore
+	l.wakeWaiters(len(l.waiters))
+	l.notifyChange()
+	l.closeWatermarks()
+
+	// From now on persistence operations are performed synchronously
+	persistQueue := l.persistQueue
+	l.persistQueue = nil
+	l.lock.Unlock()
 
+	if persistQueue != nil {
+		close(persistQueue)
+		<-l.persistDone
+	}
+
+	return nil
+}
+
+// Append one or more items to the end of the list
+// If the list is full (see WithCapacity), Push blocks until all items fit. If the items do not fit into WithMaxPersistedBytes
+// even after dropping all previous items, none of them is added and ErrListFull is passed to the errorHandler
+func (l *ConcurrentList) Push(items ...interface{}) {
+	l.push(items, pushOptions{})
+}
+
+// TryPush appends as many items as the capacity of the list allows (see WithCapacity) without blocking.
+// Returns how many items (from the front of items) were accepted and ErrListFull if that are not all of them
+func (l *ConcurrentList) TryPush(items ...interface{}) (accepted int, err error) {
+	_, accepted = l.push(items, pushOptions{try: true})
+	if accepted < len(items) {
+		return accepted, ErrListFull
+	}
+	return accepted, nil
+}
+
+// PushReport appends items just like Push. Additionally it reports if any of the items which were in the list
+// before changed their position, i.e. if WithSorting placed a pushed item in front of them or reordered them
+// (e.g. for validating that a lessFunc is stable). Without WithSorting reordered is always false
+func (l *ConcurrentList) PushReport(items ...interface{}) (reordered bool) {
+	reordered, _ = l.push(items, pushOptions{report: true})
+	return reordered
+}
+
+// PushSorted appends items which are already sorted according to the lessFunc of WithSorting (e.g. for bulk loading).
+// Instead of sorting the whole list again the items are merged into it in a single pass.
+// Without WithSorting the items are appended just like Push.
+// ATTENTION: The order of items is not checked (unless built with the debug tag), unsorted items leave the list unsorted
+func (l *ConcurrentList) PushSorted(items []interface{}) {
+	l.push(items, pushOptions{presorted: true})
+}
+
+// pushOptions modify how push adds items
+type pushOptions struct {
+	// Overrides the priority of the items (see PushWithPriority)
+	priority *int
+
+	// Report if any existing item changed its position (see PushReport)
+	report bool
+
+	// The items are sorted already (see PushSorted)
+	presorted bool
+
+	// Only add as many items as fit instead of waiting (see TryPush)
+	try bool
+
+	// Metadata of every item (see PushEntry)
+	metadata []map[string]interface{}
 }
 
-// Append to the end of the list
-func (l *ConcurrentList) Push(item interface{}) {
+// internal helper function for appending items. If opts.report is set, it returns if any existing item changed its position
+func (l *ConcurrentList) push(items []interface{}, opts pushOptions) (reordered bool, accepted int) {
+	items = l.cloneAll(items)
+	sizes := l.persistedSizes(items)
 	l.lock.Lock()
-	defer l.lock.Unlock()
 
-	l.data = append(l.data, item)
-	if l.opts.lessFunc != nil {
-		sort.Slice(l.data, func(i, j int) bool {
-			return (*l.opts.lessFunc)(l.data[i], l.data[j])
-		})
+	if l.opts.capacity > 0 {
+		if opts.try {
+			free := l.opts.capacity - len(l.data)
+			if free < 0 {
+				free = 0
+			}
+			if len(items) > free {
+				items = items[:free]
+			}
+		} else {
+			// Pushing more items than the capacity at once only has to wait for an empty list
+			for !l.closed && len(l.data) > 0 && len(l.data)+len(items) > l.opts.capacity {
+				_ = l.waitChange(context.Background())
+			}
+		}
+	}
+
+	previousLength := len(l.data)
+	var previous []*listItem
+	if opts.report && l.opts.lessFunc != nil {
+		l.applyDeferredSort()
+		previous = make([]*listItem, previousLength)
+		copy(previous, l.data)
+	}
+
+	// Only items which were in the list before are evicted to make room (see WithMaxPersistedBytes)
+	oldest := l.sequence
+	persistedBytes := l.persistedBytes()
+
+	// Items whose key is in the list already are skipped (see WithUniqueness)
+	keys := l.uniqueKeys()
+	added := make([]interface{}, 0, len(items))
+	listedItems := make([]*listItem, 0, len(items))
+	accepted = len(items)
+
+	// Push adds either all items or none of them (see WithMaxPersistedBytes), only TryPush accepts the ones in front
+	if sizes != nil && !opts.try && !l.fitPersistedBytes(items, sizes, keys) {
+		l.handleError(fmt.Errorf("%w: %d items do not fit into WithMaxPersistedBytes", ErrListFull, len(items)))
+		items = nil
+		accepted = 0
+	}
+
+	pushedAt := time.Now()
+	for i, item := range items {
+		if keys != nil {
+			key := (*l.opts.uniqueKeyFunc)(item)
+			if keys[key] {
+				continue
+			}
+			keys[key] = true
+		}
+
+		listed := l.newListItem(item, pushedAt)
+		listed.priority = opts.priority
+		if opts.metadata != nil {
+			listed.metadata = opts.metadata[i]
+		}
+		if sizes != nil {
+			listed.size = sizes[i]
+			// Nothing is dropped for items which do not fit anyway
+			if listed.size <= l.opts.maxPersistedBytes {
+				persistedBytes = l.evictPersistedBytes(persistedBytes, listed.size, oldest)
+			}
+			if persistedBytes+listed.size > l.opts.maxPersistedBytes {
+				accepted = i
+				break
+			}
+			persistedBytes += listed.size
+		}
+		l.data = append(l.data, listed)
+		added = append(added, item)
+		listedItems = append(listedItems, listed)
+	}
+	items = added
+	l.dataChanged()
+	l.countPushed(int64(len(items)))
+	switch {
+	case l.opts.deferredSort > 0 && !opts.report:
+		l.deferSort()
+	case opts.presorted:
+		// Items in front of the pushed ones may have been dropped (see WithMaxPersistedBytes)
+		l.mergeSorted(len(l.data) - len(items))
+	default:
+		l.sortData()
+	}
+
+	for i := range previous {
+		if i >= len(l.data) || previous[i] != l.data[i] {
+			reordered = true
+			break
+		}
 	}
 
 	// Write a single file per item in a directory
 	if l.opts.persistChanges {
-		err := l.persistenceCreateFile(item)
-		if err != nil && l.opts.persistErrorHandler != nil {
-			(*l.opts.persistErrorHandler)(err)
+		for _, item := range listedItems {
+			l.persistCreate(item)
 		}
 	}
 
 	// fmt.Println("count", len(l.data))
 
-	l.notEmpty.Signal()
+	l.signalNonEmpty(previousLength)
+	l.wakeForItems(len(items))
+
+	l.lock.Unlock()
+
+	// Call hooks without holding the lock, so they can use the list themselves
+	if l.opts.onPush != nil {
+		for _, item := range items {
+			(*l.opts.onPush)(item)
+		}
+	}
+
+	return reordered, accepted
+}
+
+// ReplaceAll atomically replaces all items of the list with the passed items (e.g. for refreshing a cache)
+// The files of the previous items are deleted and the new items are persisted
+func (l *ConcurrentList) ReplaceAll(items []interface{}) {
+	items = l.cloneAll(items)
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.loadAll()
+	previousLength := len(l.data)
+	atomic.AddInt64(l.totalConsumed, int64(previousLength))
+	for _, item := range l.data {
+		if l.opts.persistChanges {
+			l.persistDelete(item.value)
+		}
+		l.discard(item.value)
+	}
+
+	pushedAt := time.Now()
+	l.data = make([]*listItem, 0, len(items))
+	for _, item := range items {
+		l.data = append(l.data, l.newListItem(item, pushedAt))
+	}
+	l.dataChanged()
+	l.countPushed(int64(len(items)))
+	l.sortData()
+
+	if l.opts.persistChanges {
+		for _, item := range l.data {
+			l.persistCreate(item)
+		}
+	}
+
+	l.signalNonEmpty(previousLength)
+	l.wakeForItems(len(items))
+}
+
+// InsertAt inserts an item at the passed position of the list (0 inserts in front of all items, Length() appends)
+// Returns ErrSortingEnabled if WithSorting is used and ErrIndexOutOfRange for positions which do not exist
+func (l *ConcurrentList) InsertAt(index int, item interface{}) error {
+	item = l.clone(item)
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.opts.lessFunc != nil {
+		return ErrSortingEnabled
+	}
+	if index < 0 || index > len(l.data) {
+		return ErrIndexOutOfRange
+	}
+	if l.opts.capacity > 0 && len(l.data) >= l.opts.capacity {
+		return ErrListFull
+	}
+
+	previousLength := len(l.data)
+	l.data = append(l.data, nil)
+	copy(l.data[index+1:], l.data[index:])
+	l.data[index] = l.newListItem(item, time.Now())
+	l.dataChanged()
+	l.countPushed(1)
+
+	if l.opts.persistChanges {
+		l.persistCreate(l.data[index])
+	}
+
+	l.signalNonEmpty(previousLength)
+	l.wakeForItems(1)
+	return nil
+}
+
+// RemoveAt removes and returns the item at the passed position of the list
+// Returns ErrIndexOutOfRange for positions which do not exist
+func (l *ConcurrentList) RemoveAt(index int) (interface{}, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.loadAll()
+
+	if index < 0 || index >= len(l.data) {
+		return nil, ErrIndexOutOfRange
+	}
+
+	item := l.data[index].value
+	l.removeIndex(index)
+	l.dataChanged()
+
+	if l.opts.persistChanges {
+		l.persistDelete(item)
+	}
+
+	return item, nil
 }
 
 // Shift attempts to get the "oldest" item from the list
@@ -120,168 +622,1504 @@ func (l *ConcurrentList) Shift() (interface{}, error) {
 	return l.shift()
 }
 
+// Pop attempts to get the "newest" item from the list (the last one, i.e. the LIFO counterpart of Shift)
+// With WithSorting this is the greatest item according to lessFunc. Will return ErrEmptyList if the list is empty
+func (l *ConcurrentList) Pop() (interface{}, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.pop()
+}
+
+// CompareAndShift removes and returns the first item of the list, but only if it is equal to expected (according to equal),
+// e.g. for consuming an item which was peeked before without another consumer taking it in between.
+// Returns false without changing the list otherwise (or if the list is empty)
+func (l *ConcurrentList) CompareAndShift(expected interface{}, equal func(a, b interface{}) bool) (interface{}, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.dropExpiredHead()
+	l.loadHead()
+	if len(l.data) < 1 || !equal(l.data[0].value, expected) {
+		return nil, false
+	}
+
+	item, err := l.shift()
+	if err != nil {
+		return nil, false
+	}
+	return item, true
+}
+
+// GetNextOrDefault gets the "oldest" item from the list just like Shift, but returns the passed
+// default instead of an error if the list is empty. It never blocks
+func (l *ConcurrentList) GetNextOrDefault(def interface{}) interface{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	item, err := l.shift()
+	if err != nil {
+		return def
+	}
+	return item
+}
+
 func (l *ConcurrentList) Peek() (interface{}, error) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
+	l.loadHead()
+	l.dropExpiredHead()
 
 	if len(l.data) < 1 {
 		return nil, ErrEmptyList
 	}
 
-	firstElement := l.data[0]
+	firstElement := l.data[0].value
 	return firstElement, nil
 }
 
-// Gets the "oldest" item in the list. Blocks until an item is available or the
-// passed in context expires
-func (l *ConcurrentList) GetNext(ctx context.Context) (interface{}, error) {
+// PeekWithContext works like Peek, but waits until an item is available or the passed context expires (ErrEmptyList)
+// The item is not removed, so it might be taken by someone else right after it was returned
+func (l *ConcurrentList) PeekWithContext(ctx context.Context) (interface{}, error) {
 	l.lock.Lock()
-	atomic.AddInt64(l.runningWaitRoutines, 1)
-	// fmt.Printf("waitCount %d\n", *l.runningWaitRoutines)
-
-	useCtx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	defer l.lock.Unlock()
 
-	// Start one routine which wakes the other one up after the context expired
-	go func() {
-		atomic.AddInt64(l.runningSignalRoutines, 1)
-		// fmt.Printf("signalerCount %d\n", *l.runningSignalRoutines)
-		<-useCtx.Done()
-		l.notEmpty.Signal()
-		atomic.AddInt64(l.runningSignalRoutines, -1)
-		// fmt.Printf("signalerCount %d\n", *l.runningSignalRoutines)
-	}()
-
-	// Wait until we have something or the context expired
-	for len(l.data) == 0 || ctx.Err() != nil {
-		if err := ctx.Err(); err != nil {
-			atomic.AddInt64(l.runningWaitRoutines, -1)
-			// fmt.Printf("waitCount %d\n", *l.runningWaitRoutines)
-			l.lock.Unlock()
+	for {
+		l.loadHead()
+		l.dropExpiredHead()
+		if len(l.data) > 0 {
+			return l.data[0].value, nil
+		}
+		if l.closed {
+			return nil, ErrClosed
+		}
+		if err := l.waitChange(ctx); err != nil {
 			return nil, ErrEmptyList
 		}
-		l.notEmpty.Wait()
 	}
+}
 
-	data, err := l.shift()
-	atomic.AddInt64(l.runningWaitRoutines, -1)
-	// fmt.Printf("waitCount %d\n", *l.runningWaitRoutines)
-	l.lock.Unlock()
-
-	return data, err
+// PeekWithTimeout works like PeekWithContext, but waits at most timeout
+func (l *ConcurrentList) PeekWithTimeout(timeout time.Duration) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return l.PeekWithContext(ctx)
 }
 
-// GetWithFilter will get all items of the list which match a predicate WITHOUT changing the list
-// ("peek" into the list's items)
-func (l *ConcurrentList) GetWithFilter(predicate func(item interface{}) bool) []interface{} {
+// PeekWhere returns the first item (in the order of the list) for which match returns true without removing it.
+// Returns nil and false if no item matches. match is called while the list is locked, it must not use the list
+func (l *ConcurrentList) PeekWhere(match func(item interface{}) bool) (interface{}, bool) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
+	l.loadAll()
 
-	filteredItems := []interface{}{}
 	for _, item := range l.data {
-		if predicate(item) {
-			filteredItems = append(filteredItems, item)
+		if match(item.value) {
+			return item.value, true
 		}
 	}
-	return filteredItems
+	return nil, false
 }
 
-// DeleteWithFilter will get and remove all items of the list which match a predicate
-func (l *ConcurrentList) DeleteWithFilter(predicate func(item interface{}) bool) []interface{} {
+// PeekMatching returns the item which would be consumed next among the ones for which match returns true, without removing it:
+// the oldest matching item or, with WithSorting, the matching item with the highest priority. It is the same as PeekWhere
+func (l *ConcurrentList) PeekMatching(match func(item interface{}) bool) (interface{}, bool) {
+	return l.PeekWhere(match)
+}
+
+// Rank returns the position (zero-based) of the first item for which match returns true, i.e. how many items are
+// consumed before it (with WithSorting this reflects its priority). Returns false if no item matches.
+// match is called while the list is locked, it must not use the list
+func (l *ConcurrentList) Rank(match func(item interface{}) bool) (int, bool) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
+	l.loadAll()
 
-	nonFilteredItems := []interface{}{}
-	filteredItems := []interface{}{}
-	for _, item := range l.data {
-		if !predicate(item) {
-			nonFilteredItems = append(nonFilteredItems, item)
-		} else {
-			filteredItems = append(filteredItems, item)
-		}
-	}
-
-	// Delete all filtered files in the persistance directory
-	if l.opts.persistChanges {
-		for _, item := range filteredItems {
-			err := l.persistenceDeleteFile(item)
-			if err != nil && l.opts.persistErrorHandler != nil {
-				(*l.opts.persistErrorHandler)(err)
-			}
+	for i, item := range l.data {
+		if match(item.value) {
+			return i, true
 		}
 	}
+	return 0, false
+}
 
-	// Keep non-filtered items
-	l.data = nonFilteredItems
+// CountByKey returns how many items of the list there are per key (determined by keyFunc) in a single pass.
+// keyFunc is called while the list is locked, it must not use the list
+func (l *ConcurrentList) CountByKey(keyFunc func(item interface{}) string) map[string]int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.loadAll()
 
-	// Return filtered ones
-	return filteredItems
+	counts := map[string]int{}
+	for _, item := range l.data {
+		counts[keyFunc(item.value)]++
+	}
+	return counts
 }
 
-// Length returns the length of the list
-func (l *ConcurrentList) Length() int {
+// WaitConsumed blocks until no item for which match returns true is in the list anymore, i.e. until all of them were
+// consumed or removed, or until the passed context expires (returns the error of the context). Returns right away if no item matches.
+// match is called while the list is locked, it must not use the list
+func (l *ConcurrentList) WaitConsumed(ctx context.Context, match func(item interface{}) bool) error {
 	l.lock.Lock()
 	defer l.lock.Unlock()
-	return len(l.data)
+
+	for {
+		l.loadAll()
+		found := false
+		for _, item := range l.data {
+			if match(item.value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+
+		if err := l.waitChange(ctx); err != nil {
+			return err
+		}
+	}
 }
 
-// for testing. The metrics tell the caller how many goroutines are
-// running in order to service the concurrentList
-func (l *ConcurrentList) debug() (int64, int64) {
-	return atomic.LoadInt64(l.runningWaitRoutines), atomic.LoadInt64(l.runningSignalRoutines)
+// NotifyNonEmpty returns a channel which receives whenever the list goes from empty to non-empty.
+// The channel is shared by all callers and has a buffer of one: signals are never blocking and
+// multiple transitions which were not received yet are coalesced into a single one
+func (l *ConcurrentList) NotifyNonEmpty() <-chan struct{} {
+	return l.nonEmpty
 }
 
-// internal helper function for getting the first item. the caller needs to make sure the collection is locked
-func (l *ConcurrentList) shift() (interface{}, error) {
+// PeekLast returns the last item of the list without removing it: the newest item or,
+// with WithSorting, the greatest item according to lessFunc (i.e. the one with the lowest priority)
+// Will return ErrEmptyList if the list is empty
+func (l *ConcurrentList) PeekLast() (interface{}, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.loadAll()
+
 	if len(l.data) < 1 {
 		return nil, ErrEmptyList
 	}
 
-	firstElement := l.data[0]
-	l.data = l.data[1:len(l.data)]
+	return l.data[len(l.data)-1].value, nil
+}
 
-	// Delete the single file in our persistanceDirectory
-	if l.opts.persistChanges {
-		err := l.persistenceDeleteFile(firstElement)
-		if err != nil && l.opts.persistErrorHandler != nil {
-			(*l.opts.persistErrorHandler)(err)
-		}
-	}
+// At returns the item at the passed position (zero-based, in the order items are consumed) without removing it
+// Will return ErrIndexOutOfRange if there is no item at that position
+func (l *ConcurrentList) At(index int) (interface{}, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.loadAll()
 
-	// fmt.Println("count", len(l.data))
+	if index < 0 || index >= len(l.data) {
+		return nil, ErrIndexOutOfRange
+	}
 
-	return firstElement, nil
+	return l.data[index].value, nil
 }
 
-func (l *ConcurrentList) persistenceLoad() error {
-	files, err := ioutil.ReadDir(l.opts.persistRootPath)
-	if err != nil {
-		return err
+// TopK returns (at most) the first k items of the list without removing them.
+// With WithSorting these are the k smallest items according to lessFunc (i.e. the items with the highest priority) in sorted order,
+// otherwise these are the k oldest items
+func (l *ConcurrentList) TopK(k int) []interface{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.loadAll()
+
+	if k > len(l.data) {
+		k = len(l.data)
+	}
+	if k < 0 {
+		k = 0
 	}
 
-	for _, file := range files {
-		tmp := reflect.New(reflect.TypeOf(l.opts.persistItemType)).Interface()
-		marshaled, err := ioutil.ReadFile(filepath.Join(l.opts.persistRootPath, file.Name()))
-		if err != nil {
-			return err
+	topK := []interface{}{}
+	for _, item := range l.data[:k] {
+		topK = append(topK, item.value)
+	}
+	return topK
+}
+
+// GetNextAcceptable gets the "oldest" item which is accepted by the passed func. Blocks until an item is accepted or the
+// passed in context expires. For every item (oldest first) accept decides:
+// - ok: the item is removed from the list and returned
+// - !ok && !giveUp: the item is deferred, it stays in the list and is moved behind all other items once another item is
+// accepted (it keeps its position in a sorted list)
+// - !ok && giveUp: the item is removed from the list and dropped (see WithOnDiscard)
+// If no item is accepted, GetNextAcceptable waits until items are added or removed before checking all items again
+func (l *ConcurrentList) GetNextAcceptable(ctx context.Context, accept func(item interface{}) (ok bool, giveUp bool)) (interface{}, error) {
+	if ctx.Err() != nil {
+		return nil, ErrEmptyList
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for {
+		l.loadAll()
+		deferred := []*listItem{}
+		for i := 0; i < len(l.data); i++ {
+			item := l.data[i]
+			ok, giveUp := accept(item.value)
+
+			switch {
+			case ok:
+				// The accepted item is moved to the head, so it is taken just like by GetNext
+				remaining := l.data[i+1:]
+				if l.opts.lessFunc == nil {
+					l.data = append(append([]*listItem{item}, remaining...), deferred...)
+				} else {
+					l.data = append(append([]*listItem{item}, deferred...), remaining...)
+				}
+				return l.takeItem(0).value, nil
+			case giveUp:
+				l.removeIndex(i)
+				l.dataChanged()
+				if l.opts.persistChanges {
+					l.persistDelete(item.value)
+				}
+				l.discard(item.value)
+				i--
+			default:
+				deferred = append(deferred, item)
+			}
+		}
+
+		// Nothing acceptable: wait until something changes instead of spinning
+		if l.closed {
+			return nil, ErrClosed
+		}
+		if err := l.waitChange(ctx); err != nil {
+			return nil, ErrEmptyList
+		}
+	}
+}
+
+// Gets the "oldest" item in the list. Blocks until an item is available or the
+// passed in context expires
+func (l *ConcurrentList) GetNext(ctx context.Context) (item interface{}, err error) {
+	if l.opts.tracer != nil {
+		started := l.traceGetNextStart(ctx)
+		defer func() { l.traceGetNextEnd(ctx, started, err) }()
+	}
+
+	// There is no need for waiting if the context already expired
+	if ctx.Err() != nil {
+		return nil, ErrEmptyList
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err := l.waitForItemRecorded(ctx); err != nil {
+		return nil, err
+	}
+
+	return l.shift()
+}
+
+// PopNext gets the "newest" item in the list (see Pop). Blocks until an item is available or the
+// passed in context expires, exactly like GetNext (including WithTracer)
+func (l *ConcurrentList) PopNext(ctx context.Context) (item interface{}, err error) {
+	if l.opts.tracer != nil {
+		started := l.traceGetNextStart(ctx)
+		defer func() { l.traceGetNextEnd(ctx, started, err) }()
+	}
+
+	if ctx.Err() != nil {
+		return nil, ErrEmptyList
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err := l.waitForItem(ctx); err != nil {
+		return nil, err
+	}
+
+	return l.pop()
+}
+
+// GetNextOrHighWater gets the "oldest" item in the list just like GetNext. Additionally it reports
+// if the list held more than highWater items when the item was taken (e.g. for triggering load-shedding)
+func (l *ConcurrentList) GetNextOrHighWater(ctx context.Context, highWater int) (item interface{}, atHighWater bool, err error) {
+	if l.opts.tracer != nil {
+		started := l.traceGetNextStart(ctx)
+		defer func() { l.traceGetNextEnd(ctx, started, err) }()
+	}
+
+	if ctx.Err() != nil {
+		return nil, false, ErrEmptyList
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err := l.waitForItemRecorded(ctx); err != nil {
+		return nil, false, err
+	}
+
+	atHighWater = len(l.data) > highWater
+	item, err = l.shift()
+	return item, atHighWater, err
+}
+
+// GetNextBatchWindow waits for the next item just like GetNext and then keeps collecting items as they arrive (e.g. for micro-batching).
+// The batch is returned once no item arrived for quietPeriod, maxItems were collected or maxWait passed since the first item.
+// If the context expires (or the list is closed) after the first item, the items collected so far are returned without an error
+func (l *ConcurrentList) GetNextBatchWindow(ctx context.Context, maxWait time.Duration, quietPeriod time.Duration, maxItems int) ([]interface{}, error) {
+	if ctx.Err() != nil {
+		return nil, ErrEmptyList
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err := l.waitForItem(ctx); err != nil {
+		return nil, err
+	}
+	first, err := l.shift()
+	if err != nil {
+		return nil, err
+	}
+
+	batch := []interface{}{first}
+	deadline := time.Now().Add(maxWait)
+	for len(batch) < maxItems {
+		// Wait for the next arrival unless an item is available right away
+		if len(l.data) <= l.wokenWaiters {
+			wait := time.Until(deadline)
+			if wait <= 0 {
+				break
+			}
+			if wait > quietPeriod {
+				wait = quietPeriod
+			}
+
+			waitCtx, cancel := context.WithTimeout(ctx, wait)
+			err = l.waitForItem(waitCtx)
+			cancel()
+			if err != nil {
+				break
+			}
+		}
+
+		item, err := l.shift()
+		if err != nil {
+			break
+		}
+		batch = append(batch, item)
+	}
+
+	return batch, nil
+}
+
+// GetNextNMin waits until at least min items are available and then takes up to max items at once (e.g. for amortizing the cost
+// of processing a batch). Once maxWait passed, it takes whatever is available instead, but still waits for at least one item.
+// Returns ErrEmptyList if the context expires before the batch was taken and ErrClosed if the list is closed while it is empty.
+// Returns ErrInvalidArgument right away unless 0 < min <= max
+func (l *ConcurrentList) GetNextNMin(ctx context.Context, min, max int, maxWait time.Duration) ([]interface{}, error) {
+	if min <= 0 || min > max {
+		return nil, fmt.Errorf("%w: min %d, max %d", ErrInvalidArgument, min, max)
+	}
+	if ctx.Err() != nil {
+		return nil, ErrEmptyList
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	deadline := time.Now().Add(maxWait)
+	for {
+		l.dropExpiredHead()
+
+		// Do not count the items which are reserved for waiters which were woken up before
+		available := len(l.data) - l.wokenWaiters
+		waited := !time.Now().Before(deadline)
+		if available > 0 && (available >= min || waited || l.closed) {
+			if available > max {
+				available = max
+			}
+			batch := make([]interface{}, 0, available)
+			for len(batch) < available {
+				item, err := l.shift()
+				if err != nil {
+					break
+				}
+				batch = append(batch, item)
+			}
+			return batch, nil
+		}
+		if l.closed {
+			return nil, ErrClosed
+		}
+
+		waitCtx, cancel := ctx, func() {}
+		if !waited {
+			waitCtx, cancel = context.WithDeadline(ctx, deadline)
+		}
+		_ = l.waitChange(waitCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return nil, ErrEmptyList
+		}
+	}
+}
+
+// GetWithFilter will get all items of the list which match a predicate WITHOUT changing the list
+// ("peek" into the list's items)
+func (l *ConcurrentList) GetWithFilter(predicate func(item interface{}) bool) []interface{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.loadAll()
+
+	filteredItems := []interface{}{}
+	for _, item := range l.data {
+		if predicate(item.value) {
+			filteredItems = append(filteredItems, item.value)
+		}
+	}
+	return filteredItems
+}
+
+// GetWithFilterIndexed works like GetWithFilter, but additionally passes the index of every item within the list to predicate
+func (l *ConcurrentList) GetWithFilterIndexed(predicate func(index int, item interface{}) bool) []interface{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.loadAll()
+
+	filteredItems := []interface{}{}
+	for i, item := range l.data {
+		if predicate(i, item.value) {
+			filteredItems = append(filteredItems, item.value)
+		}
+	}
+	return filteredItems
+}
+
+// GetWithFilterContext works like GetWithFilter, but checks the passed context between items (e.g. for long running predicates)
+// If the context expires during the scan, the matches found so far are returned along with the error of the context
+func (l *ConcurrentList) GetWithFilterContext(ctx context.Context, predicate func(item interface{}) bool) ([]interface{}, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.loadAll()
+
+	filteredItems := []interface{}{}
+	for _, item := range l.data {
+		if err := ctx.Err(); err != nil {
+			return filteredItems, err
+		}
+		if predicate(item.value) {
+			filteredItems = append(filteredItems, item.value)
+		}
+	}
+	return filteredItems, nil
+}
+
+// DeleteWithFilter will get and remove all items of the list which match a predicate
+func (l *ConcurrentList) DeleteWithFilter(predicate func(item interface{}) bool) []interface{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.deleteWithFilter(func(item *listItem) bool {
+		return predicate(item.value)
+	})
+}
+
+// Partition removes all items of the list which match a predicate (like DeleteWithFilter) and additionally returns the
+// items which remain in the list, both in the order of the list. Both happen at once, nothing can change the list in between
+func (l *ConcurrentList) Partition(predicate func(item interface{}) bool) (removed []interface{}, remaining []interface{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	removed = l.deleteWithFilter(func(item *listItem) bool {
+		return predicate(item.value)
+	})
+
+	remaining = make([]interface{}, len(l.data))
+	for i, item := range l.data {
+		remaining[i] = item.value
+	}
+	return removed, remaining
+}
+
+// DeleteWithFilterIndexed works like DeleteWithFilter, but additionally passes the index of every item within the list
+// (before anything is removed) to predicate
+func (l *ConcurrentList) DeleteWithFilterIndexed(predicate func(index int, item interface{}) bool) []interface{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	// deleteWithFilter checks all items in order
+	index := -1
+	return l.deleteWithFilter(func(item *listItem) bool {
+		index++
+		return predicate(index, item.value)
+	})
+}
+
+// Drain removes and returns all items of the list (e.g. for handing them elsewhere before or after calling Close)
+// The items are passed to the hook of WithOnDiscard as well, like the ones removed by Clear
+func (l *ConcurrentList) Drain() []interface{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	drained := l.deleteWithFilter(func(item *listItem) bool {
+		return true
+	})
+	l.discard(drained...)
+	return drained
+}
+
+// DrainInto works like Drain, but appends the items to dst and returns the extended slice. If dst has enough capacity
+// its backing array is reused, so a buffer can be reused across drain cycles without allocating
+func (l *ConcurrentList) DrainInto(dst []interface{}) []interface{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.loadAll()
+	for i, item := range l.data {
+		dst = append(dst, item.value)
+		if l.opts.persistChanges {
+			l.persistDelete(item.value)
+		}
+		l.discard(item.value)
+		l.data[i] = nil
+	}
+	atomic.AddInt64(l.totalConsumed, int64(len(l.data)))
+	l.data = l.data[:0]
+	l.dataChanged()
+
+	return dst
+}
+
+// Clear removes all items of the list. In contrast to Drain the items are not returned, only passed to WithOnDiscard
+func (l *ConcurrentList) Clear() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.discard(l.deleteWithFilter(func(item *listItem) bool {
+		return true
+	})...)
+}
+
+// DeleteWithFilterContext works like DeleteWithFilter, but checks the passed context between items (e.g. for long running predicates)
+// If the context expires during the scan, nothing is deleted and the error of the context is returned
+func (l *ConcurrentList) DeleteWithFilterContext(ctx context.Context, predicate func(item interface{}) bool) ([]interface{}, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.loadAll()
+
+	// Only delete once all items have been checked, so an expired context does not leave the list half-filtered
+	matches := map[*listItem]bool{}
+	for _, item := range l.data {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if predicate(item.value) {
+			matches[item] = true
+		}
+	}
+
+	return l.deleteWithFilter(func(item *listItem) bool {
+		return matches[item]
+	}), nil
+}
+
+// internal helper function for removing all items which match a predicate. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) deleteWithFilter(predicate func(item *listItem) bool) []interface{} {
+	l.loadAll()
+	filtered := make([]bool, len(l.data))
+	filteredItems := []interface{}{}
+	for i, item := range l.data {
+		if predicate(item) {
+			filtered[i] = true
+			filteredItems = append(filteredItems, item.value)
+		}
+	}
+
+	// Delete all filtered files in the persistance directory
+	if l.opts.persistChanges {
+		for _, item := range filteredItems {
+			l.persistDelete(item)
+		}
+	}
+
+	// Keep non-filtered items by moving them to the front of the backing array, which is reused.
+	// The vacated slots must not keep the filtered items from being garbage collected
+	nonFiltered := 0
+	for i, item := range l.data {
+		if !filtered[i] {
+			l.data[nonFiltered] = item
+			nonFiltered++
+		}
+	}
+	for i := nonFiltered; i < len(l.data); i++ {
+		l.data[i] = nil
+	}
+	atomic.AddInt64(l.totalConsumed, int64(len(l.data)-nonFiltered))
+	l.data = l.data[:nonFiltered]
+	l.dataChanged()
+
+	// Return filtered ones
+	return filteredItems
+}
+
+// Compact visits every item (in the order of the list) and removes it if fn returns keep == false. Otherwise the item is
+// replaced by replacement (return the item itself for keeping it unchanged). Returns how many items were removed.
+// Files of removed items are deleted, replaced items are written again. Removed items are dropped (see WithOnDiscard).
+// fn is called while the list is locked, it must not use the list
+func (l *ConcurrentList) Compact(fn func(item interface{}) (keep bool, replacement interface{})) int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	replaced := false
+	removed := l.deleteWithFilter(func(item *listItem) bool {
+		keep, replacement := fn(item.value)
+		if !keep {
+			return true
+		}
+		if reflect.DeepEqual(replacement, item.value) {
+			return false
+		}
+
+		if l.opts.persistChanges {
+			l.persistDelete(item.value)
+		}
+		if sizes := l.persistedSizes([]interface{}{replacement}); sizes != nil {
+			item.size = sizes[0]
+		}
+		item.value = replacement
+		if l.opts.persistChanges {
+			l.persistCreate(item)
+		}
+		replaced = true
+		return false
+	})
+	if replaced {
+		l.sortData()
+	}
+	l.discard(removed...)
+	return len(removed)
+}
+
+// Remove removes all items which are equal to the passed item (according to the passed equal func)
+// and returns how many items were removed
+func (l *ConcurrentList) Remove(item interface{}, equal func(a, b interface{}) bool) int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return len(l.deleteWithFilter(func(listed *listItem) bool {
+		return equal(listed.value, item)
+	}))
+}
+
+// ShedLowest removes and returns the last n items of the list (e.g. for load shedding), in the order of the list:
+// with WithSorting (or a priority list) these are the items which would be consumed last, otherwise the newest ones
+func (l *ConcurrentList) ShedLowest(n int) []interface{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.loadAll()
+	first := len(l.data) - n
+	index := -1
+	return l.deleteWithFilter(func(item *listItem) bool {
+		index++
+		return index >= first
+	})
+}
+
+// PopMatching removes and returns up to max items which match a predicate, in the order of the list
+func (l *ConcurrentList) PopMatching(match func(item interface{}) bool, max int) []interface{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	matched := 0
+	return l.deleteWithFilter(func(item *listItem) bool {
+		if matched >= max || !match(item.value) {
+			return false
+		}
+		matched++
+		return true
+	})
+}
+
+// Length returns the length of the list
+// It does not acquire the lock of the list and can therefore be polled frequently
+func (l *ConcurrentList) Length() int {
+	return int(atomic.LoadInt64(l.length))
+}
+
+// OldestAge returns how long the first item of the list has been waiting since it was pushed
+// Will return ErrEmptyList if the list is empty
+func (l *ConcurrentList) OldestAge() (time.Duration, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.applyDeferredSort()
+
+	if len(l.data) < 1 {
+		return 0, ErrEmptyList
+	}
+
+	return time.Since(l.data[0].pushedAt), nil
+}
+
+// TotalPushed returns how many items were added to the list since it was created (items which are reconstructed from persistence are not counted)
+func (l *ConcurrentList) TotalPushed() int64 {
+	return atomic.LoadInt64(l.totalPushed)
+}
+
+// TotalConsumed returns how many items left the list since it was created, no matter how (e.g. Shift, GetNext, DeleteWithFilter,
+// Drain, ReplaceAll or evictions of WithTTL)
+func (l *ConcurrentList) TotalConsumed() int64 {
+	return atomic.LoadInt64(l.totalConsumed)
+}
+
+// EvictedCount returns how many items were removed because their ttl expired (see WithTTL, WithAutoTTL and WithLazyExpiry)
+func (l *ConcurrentList) EvictedCount() int64 {
+	return atomic.LoadInt64(l.ttlEvictions)
+}
+
+// PersistenceDir returns the rootPath of WithPersistence and whether the list is persisted in a directory at all
+func (l *ConcurrentList) PersistenceDir() (string, bool) {
+	return l.opts.persistRootPath, l.persistFiles()
+}
+
+// PersistenceHealthy reports if the last file of WithPersistence was written (or deleted) successfully.
+// The list keeps working in memory if persistence fails (e.g. because the directory became read-only),
+// it becomes healthy again with the next successful operation. Always true without persistence
+func (l *ConcurrentList) PersistenceHealthy() bool {
+	return atomic.LoadInt64(l.persistFailing) == 0
+}
+
+// Errors returns all errors (persistence and ttl) which were collected since the last call, oldest first
+// Errors are only collected if no errorHandler is passed to WithPersistence. At most the
+// size passed to WithErrorBufferSize is kept, older errors are discarded
+func (l *ConcurrentList) Errors() []error {
+	l.errorsLock.Lock()
+	defer l.errorsLock.Unlock()
+
+	errs := l.errors
+	l.errors = []error{}
+	return errs
+}
+
+// for testing. The metrics tell the caller how many goroutines are
+// running in order to service the concurrentList and how many of them are registered as waiting
+func (l *ConcurrentList) debug() (int64, int64) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return atomic.LoadInt64(l.runningWaitRoutines), int64(len(l.waiters))
+}
+
+// internal helper function for passing an error to the errorHandler or collecting it if there is none
+func (l *ConcurrentList) handleError(err error) {
+	if l.opts.persistErrorHandler != nil {
+		(*l.opts.persistErrorHandler)(err)
+		return
+	}
+
+	if l.opts.errorBufferSize < 1 {
+		return
+	}
+
+	l.errorsLock.Lock()
+	defer l.errorsLock.Unlock()
+
+	if len(l.errors) >= l.opts.errorBufferSize {
+		l.errors = l.errors[len(l.errors)-l.opts.errorBufferSize+1:]
+	}
+	l.errors = append(l.errors, err)
+}
+
+// internal helper function which needs to be called whenever items are added or removed. It keeps the length in sync with data
+// and wakes up everyone waiting for a change. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) dataChanged() {
+	atomic.StoreInt64(l.length, int64(len(l.data)))
+	l.notifyChange()
+	l.checkWatermarks()
+}
+
+// internal helper function for counting added items. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) countPushed(n int64) {
+	atomic.AddInt64(l.totalPushed, n)
+	if n > 0 {
+		l.lastPushedAt = time.Now()
+	}
+}
+
+// internal helper function for counting an item which was taken from the head of the list. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) countShifted() {
+	atomic.AddInt64(l.totalShifted, 1)
+	l.lastShiftedAt = time.Now()
+}
+
+// internal helper function for signaling a transition from empty to non-empty. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) signalNonEmpty(previousLength int) {
+	if previousLength > 0 || len(l.data) == 0 {
+		return
+	}
+
+	select {
+	case l.nonEmpty <- struct{}{}:
+	default:
+	}
+}
+
+// internal helper function for copying an item before it is added to the list (see WithDeepCopy)
+func (l *ConcurrentList) clone(item interface{}) interface{} {
+	if l.opts.cloneFunc == nil {
+		return item
+	}
+	return (*l.opts.cloneFunc)(item)
+}
+
+// internal helper function for copying items before they are added to the list (see WithDeepCopy)
+// The passed slice is not modified
+func (l *ConcurrentList) cloneAll(items []interface{}) []interface{} {
+	if l.opts.cloneFunc == nil {
+		return items
+	}
+
+	cloned := make([]interface{}, len(items))
+	for i, item := range items {
+		cloned[i] = (*l.opts.cloneFunc)(item)
+	}
+	return cloned
+}
+
+// internal helper function for checking if the ttl of an item has expired
+func (l *ConcurrentList) expired(item *listItem) bool {
+	addedAt := item.pushedAt
+	if l.opts.ttlFunc != nil {
+		addedAt = (*l.opts.ttlFunc)(item.value)
+	}
+	if time.Since(addedAt) <= *l.opts.ttlDuration {
+		return false
+	}
+	return l.opts.ttlKeepAlive == nil || !(*l.opts.ttlKeepAlive)(item.value)
+}
+
+// SweepExpiredNow removes all items which expired according to WithTTL right away and returns them, independent of the
+// check interval of WithTTL (e.g. for deterministic tests). Returns nil without WithTTL
+func (l *ConcurrentList) SweepExpiredNow() []interface{} {
+	if !l.opts.ttlEnabled {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.sweepExpired()
+}
+
+// internal helper function for removing all expired items (see WithTTL). the caller needs to make sure the collection is locked
+func (l *ConcurrentList) sweepExpired() []interface{} {
+	// There is nothing to sweep in an empty list
+	if len(l.data) == 0 {
+		return nil
+	}
+
+	evicted := l.deleteWithFilter(l.expired)
+	atomic.AddInt64(l.ttlEvictions, int64(len(evicted)))
+	l.discard(evicted...)
+	return evicted
+}
+
+// internal helper function for determining when the next item expires (see WithTTL). Items which are kept alive although
+// they expired already are not considered. Returns false if no item is going to expire. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) nextExpiry() (time.Time, bool) {
+	l.loadAll()
+	return l.nextLoadedExpiry()
+}
+
+// internal helper function for determining when the next item expires just like nextExpiry, but without loading any
+// item (see WithLazyPersistenceLoad): items which are not loaded yet are not considered. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) nextLoadedExpiry() (time.Time, bool) {
+	var next time.Time
+	found := false
+	for _, item := range l.data {
+		if item.lazyPath != "" {
+			continue
 		}
-		err = json.Unmarshal(marshaled, &tmp)
+		addedAt := item.pushedAt
+		if l.opts.ttlFunc != nil {
+			addedAt = (*l.opts.ttlFunc)(item.value)
+		}
+		expiry := addedAt.Add(*l.opts.ttlDuration)
+		if time.Now().After(expiry) {
+			continue
+		}
+		if !found || expiry.Before(next) {
+			next = expiry
+			found = true
+		}
+	}
+	return next, found
+}
+
+// SetLessFunc replaces the lessFunc of WithSorting and immediately re-sorts the list accordingly (e.g. for switching the sort order at runtime)
+// Passing nil turns sorting off: the items keep their current order and pushed items are appended from then on.
+// ATTENTION: In a list created by NewPriorityList or NewMaxPriorityList this replaces the priority as well, PushWithPriority
+// returns ErrPriorityDisabled afterwards and previously overridden priorities are ignored
+func (l *ConcurrentList) SetLessFunc(lessFunc func(i, j interface{}) bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.opts.priorityFunc = nil
+	if lessFunc == nil {
+		l.opts.lessFunc = nil
+		return
+	}
+
+	l.opts.lessFunc = &lessFunc
+	l.loadAll()
+	l.sortData()
+}
+
+// IsSorted returns whether the list keeps its items sorted (see WithSorting and SetLessFunc)
+func (l *ConcurrentList) IsSorted() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.opts.lessFunc != nil
+}
+
+// VerifySorted checks whether the items are actually in the order of the lessFunc (e.g. for debugging items which
+// were modified after they were added). A list which is not sorted is always in order
+func (l *ConcurrentList) VerifySorted() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.opts.lessFunc == nil {
+		return true
+	}
+
+	l.loadAll()
+	for i := 1; i < len(l.data); i++ {
+		if l.less(l.data[i], l.data[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// internal helper function for sorting the list if WithSorting is used. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) sortData() {
+	l.sortPending = false
+	if l.opts.lessFunc != nil {
+		atomic.AddInt64(l.sorts, 1)
+		// Equal items keep the order they were pushed in
+		sort.Slice(l.data, func(i, j int) bool {
+			if l.less(l.data[i], l.data[j]) {
+				return true
+			}
+			if l.less(l.data[j], l.data[i]) {
+				return false
+			}
+			return l.data[i].sequence < l.data[j].sequence
+		})
+	}
+}
+
+// internal helper function for merging the sorted items starting at index into the sorted items in front of them (see PushSorted).
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) mergeSorted(index int) {
+	if l.opts.lessFunc == nil {
+		return
+	}
+
+	existing, added := l.data[:index], l.data[index:]
+	if debug {
+		for i := 1; i < len(added); i++ {
+			if l.less(added[i], added[i-1]) {
+				panic(fmt.Sprintf("concurrentList: PushSorted with unsorted items (%v before %v)", added[i-1].value, added[i].value))
+			}
+		}
+	}
+
+	// Equal items keep the order they were pushed in
+	merged := make([]*listItem, 0, len(l.data))
+	i, j := 0, 0
+	for i < len(existing) && j < len(added) {
+		if l.less(added[j], existing[i]) {
+			merged = append(merged, added[j])
+			j++
+		} else {
+			merged = append(merged, existing[i])
+			i++
+		}
+	}
+	merged = append(merged, existing[i:]...)
+	merged = append(merged, added[j:]...)
+	l.data = merged
+}
+
+// internal helper function for removing the item at index. The vacated slot of the backing array is cleared,
+// so it does not keep the removed item from being garbage collected. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) removeIndex(index int) {
+	atomic.AddInt64(l.totalConsumed, 1)
+	if index == 0 {
+		l.data[0] = nil
+		l.data = l.data[1:]
+		return
+	}
+
+	copy(l.data[index:], l.data[index+1:])
+	l.data[len(l.data)-1] = nil
+	l.data = l.data[:len(l.data)-1]
+}
+
+// internal helper function for creating an item which is added to the list. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) newListItem(value interface{}, pushedAt time.Time) *listItem {
+	l.sequence++
+	return &listItem{value: value, pushedAt: pushedAt, sequence: l.sequence}
+}
+
+// internal helper function for comparing two items of a sorted list. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) less(i, j *listItem) bool {
+	if l.opts.priorityFunc != nil {
+		return l.lessPriority(i, j)
+	}
+	return (*l.opts.lessFunc)(i.value, j.value)
+}
+
+// internal helper function for waiting until an item is available (which is not reserved for a waiter which was woken up before us)
+// or the context expired. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) waitForItem(ctx context.Context) error {
+	atomic.AddInt64(l.runningWaitRoutines, 1)
+	defer atomic.AddInt64(l.runningWaitRoutines, -1)
+
+	l.dropExpiredHead()
+	for len(l.data) <= l.wokenWaiters {
+		if l.closed {
+			return ErrClosed
+		}
+		if err := l.wait(ctx); err != nil {
+			return ErrEmptyList
+		}
+		l.dropExpiredHead()
+	}
+	return nil
+}
+
+// internal helper function for passing dropped items to the hook of WithOnDiscard. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) discard(items ...interface{}) {
+	if l.opts.onDiscard == nil {
+		return
+	}
+	for _, item := range items {
+		(*l.opts.onDiscard)(item)
+	}
+}
+
+// internal helper function for removing expired items from the head of the list (see WithLazyExpiry). the caller needs to make sure the collection is locked
+func (l *ConcurrentList) dropExpiredHead() {
+	if l.opts.lazyExpiryFunc == nil {
+		return
+	}
+
+	l.loadHead()
+	for len(l.data) > 0 && l.dropExpiredAt(0) {
+		l.loadHead()
+	}
+}
+
+// internal helper function for removing the (loaded) item at the passed position if it expired (see WithLazyExpiry).
+// Returns true if it was removed. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) dropExpiredAt(index int) bool {
+	if l.opts.lazyExpiryFunc == nil {
+		return false
+	}
+
+	expired := l.data[index].value
+	if time.Since((*l.opts.lazyExpiryFunc)(expired)) <= l.opts.lazyExpiryMaxAge {
+		return false
+	}
+	l.removeIndex(index)
+	l.dataChanged()
+	atomic.AddInt64(l.ttlEvictions, 1)
+	if l.opts.persistChanges {
+		l.persistDelete(expired)
+	}
+	l.discard(expired)
+	return true
+}
+
+// internal helper function for getting the first item. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) shift() (interface{}, error) {
+	firstElement, err := l.shiftItem()
+	if err != nil {
+		return nil, err
+	}
+	return firstElement.value, nil
+}
+
+// internal helper function for getting the first item along with its bookkeeping. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) shiftItem() (*listItem, error) {
+	l.dropExpiredHead()
+	l.loadHead()
+	if len(l.data) < 1 {
+		return nil, ErrEmptyList
+	}
+
+	return l.takeItem(0), nil
+}
+
+// internal helper function for taking the item at the passed position out of the list on behalf of a consumer
+// (counted in Stats and deleted from persistence). the caller needs to make sure the collection is locked
+func (l *ConcurrentList) takeItem(index int) *listItem {
+	item := l.data[index]
+	l.removeIndex(index)
+	l.dataChanged()
+	l.countShifted()
+
+	// Delete the single file in our persistanceDirectory
+	if l.opts.persistChanges {
+		l.persistDelete(item.value)
+	}
+	return item
+}
+
+// internal helper function for getting the last item. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) pop() (interface{}, error) {
+	l.loadAll()
+	if len(l.data) < 1 {
+		return nil, ErrEmptyList
+	}
+
+	lastElement := l.data[len(l.data)-1]
+	l.removeIndex(len(l.data) - 1)
+	l.dataChanged()
+	l.countShifted()
+
+	if l.opts.persistChanges {
+		l.persistDelete(lastElement.value)
+	}
+
+	return lastElement.value, nil
+}
+
+// Create the persistence directory (if it does not exist yet) and reconstruct the persisted list
+func (l *ConcurrentList) persistenceInit() error {
+	if !l.opts.persistChanges {
+		return nil
+	}
+
+	// Without a directory the list is reconstructed from the first backend (see WithPersistenceBackend)
+	if !l.persistFiles() {
+		return l.persistenceLoadBackend()
+	}
+
+	err := os.MkdirAll(l.opts.persistRootPath, 0755)
+	if err != nil {
+		return err
+	}
+
+	err = l.persistenceLoad()
+	if err != nil {
+		return err
+	}
+
+	// Items which are equal according to lessFunc are sorted in the order they were pushed in before, which is restored
+	// from the sequences persisted along with them. Files which were written without one come first
+	if l.opts.lessFunc != nil {
+		sort.SliceStable(l.data, func(i, j int) bool {
+			return l.data[i].sequence < l.data[j].sequence
+		})
+		for i, item := range l.data {
+			item.sequence = uint64(i + 1)
+		}
+		l.sequence = uint64(len(l.data))
+		l.sortData()
+	}
+	l.persistenceDedup()
+	return nil
+}
+
+func (l *ConcurrentList) persistenceLoad() error {
+	return l.persistenceLoadDir(l.opts.persistRootPath, l.opts.persistShardFunc != nil, map[string]bool{})
+}
+
+// loaded holds the paths of all items which were loaded so far (see WithLoadDuplicatePolicy)
+func (l *ConcurrentList) persistenceLoadDir(dir string, sharded bool, loaded map[string]bool) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		itemPath := filepath.Join(dir, file.Name())
+
+		// Every shard is a subdirectory of the rootPath (see WithShardedPersistence)
+		if sharded && file.IsDir() {
+			err = l.persistenceLoadDir(itemPath, false, loaded)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Only remember where the item is, it is reconstructed once it is needed (see WithLazyPersistenceLoad).
+		// Sorting requires all items anyway
+		if l.opts.persistLazyLoad && l.opts.persistDuplicatePolicy == DuplicatesKeepAll && l.persistVersions == nil && l.opts.lessFunc == nil {
+			listed := l.newListItem(nil, file.ModTime())
+			listed.lazyPath = itemPath
+			listed.size = file.Size()
+			l.data = append(l.data, listed)
+			l.lazyPending++
+			l.dataChanged()
+			if l.persistRefs != nil {
+				l.persistRefs[itemPath]++
+			}
+			continue
+		}
+
+		item, sequence, err := l.persistenceReadFile(itemPath)
 		if err != nil {
 			return err
 		}
-		// Make sure we are not storing a pointer to our item
-		l.data = append(l.data, reflect.ValueOf(tmp).Elem().Interface())
+
+		// Multiple files can hold the same item (e.g. if the fileNameFunc changed)
+		if l.opts.persistDuplicatePolicy != DuplicatesKeepAll {
+			path := l.persistencePath(item)
+			if loaded[path] {
+				if l.opts.persistDuplicatePolicy == DuplicatesError {
+					return fmt.Errorf("%w: %s", ErrDuplicateItem, itemPath)
+				}
+				err = os.Remove(itemPath)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			loaded[path] = true
+		}
+
+		listed := l.newListItem(item, file.ModTime())
+		listed.size = file.Size()
+		if l.opts.lessFunc != nil {
+			// Only used for restoring the order, see persistenceInit
+			listed.sequence = sequence
+		}
+		l.data = append(l.data, listed)
+		l.dataChanged()
+		if l.persistRefs != nil {
+			l.persistRefs[itemPath]++
+		}
 	}
 
 	return nil
 }
 
-func (l *ConcurrentList) persistenceCreateFile(item interface{}) error {
-	marshaled, err := json.Marshal(item)
+// reconstruct a single item and the sequence it was persisted with (see persistedRecord) from its file
+// (repairing it if necessary, see WithReadRepair)
+func (l *ConcurrentList) persistenceReadFile(itemPath string) (interface{}, uint64, error) {
+	contents, err := ioutil.ReadFile(itemPath)
+	if err != nil {
+		return nil, 0, err
+	}
+	marshaled, sequence := l.persistenceUnwrapRecord(itemPath, contents)
+	item, err := l.persistenceUnmarshal(marshaled)
+	if err != nil && l.opts.persistReadRepair != nil {
+		item, err = l.persistenceRepair(itemPath, marshaled, sequence)
+	}
+	return item, sequence, err
+}
+
+// reconstruct a single item from the contents of its file
+// Items implementing encoding.BinaryUnmarshaler are reconstructed with UnmarshalBinary instead of json
+func (l *ConcurrentList) persistenceUnmarshal(marshaled []byte) (interface{}, error) {
+	tmp := reflect.New(reflect.TypeOf(l.opts.persistItemType)).Interface()
+	var err error
+	if unmarshaler, ok := tmp.(encoding.BinaryUnmarshaler); ok {
+		err = unmarshaler.UnmarshalBinary(marshaled)
+	} else {
+		err = json.Unmarshal(marshaled, &tmp)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Make sure we are not storing a pointer to our item
+	return reflect.ValueOf(tmp).Elem().Interface(), nil
+}
+
+// pass the contents of a file which could not be reconstructed to the repair func of WithReadRepair
+// and rewrite the file if the repaired contents can be reconstructed
+func (l *ConcurrentList) persistenceRepair(itemPath string, marshaled []byte, sequence uint64) (interface{}, error) {
+	repaired, err := (*l.opts.persistReadRepair)(marshaled)
+	if err != nil {
+		return nil, err
+	}
+	item, err := l.persistenceUnmarshal(repaired)
+	if err != nil {
+		return nil, err
+	}
+	repaired, err = l.persistenceWrapRecord(item, itemPath, sequence, repaired)
+	if err != nil {
+		return nil, err
+	}
+	err = l.persistenceWriteFile(itemPath, repaired)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// internal helper function for writing the file of an item, either directly or by the persistence worker (see WithAsyncPersistence).
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) persistCreate(item *listItem) {
+	if l.persistSuspended {
+		return
+	}
+	op := l.persistenceOperation(item.value, false)
+	op.sequence = l.persistedSequence(item)
+	if l.persistQueue != nil {
+		l.persistenceEnqueue(op)
+		return
+	}
+
+	l.persistenceApply(op)
+}
+
+// internal helper function for deleting the file of an item, either directly or by the persistence worker (see WithAsyncPersistence).
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) persistDelete(item interface{}) {
+	if l.persistSuspended {
+		return
+	}
+	op := l.persistenceOperation(item, true)
+	if l.persistQueue != nil {
+		l.persistenceEnqueue(op)
+		return
+	}
+
+	l.persistenceApply(op)
+}
+
+// internal helper function for preparing a persistence operation: the path of the file is determined and the files shared by
+// identical items are counted (see WithContentHashPersistence) right away, so performing the operation later on (see WithAsyncPersistence
+// and WithPersistenceTimeout) does not touch the state of the list. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) persistenceOperation(item interface{}, remove bool) persistOperation {
+	op := persistOperation{item: item, delete: remove}
+	if !l.persistFiles() {
+		return op
+	}
+	op.itemPath = l.persistencePath(item)
+	op.file = true
+
+	// Identical items share a single file which is only written for the first and only deleted for the last one
+	if l.persistRefs != nil {
+		if remove {
+			l.persistRefs[op.itemPath]--
+			if l.persistRefs[op.itemPath] > 0 {
+				op.file = false
+			} else {
+				delete(l.persistRefs, op.itemPath)
+			}
+		} else {
+			l.persistRefs[op.itemPath]++
+			op.file = l.persistRefs[op.itemPath] == 1
+		}
+	}
+	return op
+}
+
+// internal helper function for getting the sequence the file of an item is written with: equal items of a sorted list are
+// reconstructed in the order they were pushed in (see persistenceInit), 0 if the list is not sorted.
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) persistedSequence(item *listItem) uint64 {
+	if l.opts.lessFunc == nil {
+		return 0
+	}
+	return item.sequence
+}
+
+// internal helper function for writing or deleting the file of an item right away
+// Operations are skipped while the circuit breaker is open (see WithPersistenceCircuitBreaker)
+func (l *ConcurrentList) persistenceApply(op persistOperation) {
+	if l.persistBreaker != nil && !l.persistBreaker.allow() {
+		return
+	}
+
+	var err error
+	if l.opts.persistTimeout > 0 {
+		err = l.persistenceApplyTimeout(op)
+	} else {
+		err = l.persistenceApplyIO(op)
+	}
+
+	if err != nil {
+		atomic.StoreInt64(l.persistFailing, 1)
+	} else {
+		atomic.StoreInt64(l.persistFailing, 0)
+	}
+	if l.persistBreaker != nil {
+		l.persistBreaker.record(err)
+	}
+}
+
+// internal helper function for writing or deleting the file of an item and storing or deleting it in all backends.
+// Returns the first error, all errors are handled
+func (l *ConcurrentList) persistenceApplyIO(op persistOperation) error {
+	var err error
+	if op.file {
+		if op.delete {
+			err = l.persistenceDeleteFile(op.itemPath)
+		} else {
+			err = l.persistenceCreateFile(op.item, op.itemPath, op.sequence)
+		}
+		if err != nil {
+			l.handleError(err)
+		}
+	}
+	if backendErr := l.persistenceApplyBackends(op.item, op.delete); err == nil {
+		err = backendErr
+	}
+	return err
+}
+
+// the contents of the file of an item
+// Items implementing encoding.BinaryMarshaler are marshaled with MarshalBinary instead of json.
+// configure (optional, may be nil) sets up the json.Encoder the item is marshaled with (see WithJSONEncoderOptions)
+func persistenceMarshal(item interface{}, configure *func(encoder *json.Encoder)) ([]byte, error) {
+	if marshaler, ok := item.(encoding.BinaryMarshaler); ok {
+		return marshaler.MarshalBinary()
+	}
+	if configure == nil {
+		return json.Marshal(item)
+	}
+
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	(*configure)(encoder)
+	if err := encoder.Encode(item); err != nil {
+		return nil, err
+	}
+	// In contrast to json.Marshal the encoder terminates every value with a newline
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+func (l *ConcurrentList) persistenceCreateFile(item interface{}, itemPath string, sequence uint64) error {
+	marshaled, err := persistenceMarshal(item, l.opts.persistJSONEncoder)
+	if err != nil {
+		return err
+	}
+
+	marshaled, err = l.persistenceWrapRecord(item, itemPath, sequence, marshaled)
 	if err != nil {
 		return err
 	}
-	itemPath := filepath.Join(l.opts.persistRootPath, (*l.opts.persistFileNameFunc)(item))
+
+	if l.opts.persistShardFunc != nil {
+		err = os.MkdirAll(filepath.Dir(itemPath), 0755)
+		if err != nil {
+			return err
+		}
+	}
+	return l.persistenceWriteFile(itemPath, marshaled)
+}
+
+func (l *ConcurrentList) persistenceWriteFile(itemPath string, marshaled []byte) error {
 	file, err := os.Create(itemPath)
 	if err != nil {
 		return err
@@ -300,7 +2138,22 @@ func (l *ConcurrentList) persistenceCreateFile(item interface{}) error {
 	return nil
 }
 
-func (l *ConcurrentList) persistenceDeleteFile(item interface{}) error {
-	itemPath := filepath.Join(l.opts.persistRootPath, (*l.opts.persistFileNameFunc)(item))
+func (l *ConcurrentList) persistenceDeleteFile(itemPath string) error {
+	// Do not delete a file which was written by another list in the meantime (see WithVersioning)
+	if l.persistVersions != nil {
+		if err := l.persistenceForgetVersion(itemPath); err != nil {
+			return err
+		}
+	}
+
 	return os.Remove(itemPath)
 }
+
+// path of the file of an item
+func (l *ConcurrentList) persistencePath(item interface{}) string {
+	fileName := (*l.opts.persistFileNameFunc)(item)
+	if l.opts.persistShardFunc != nil {
+		return filepath.Join(l.opts.persistRootPath, (*l.opts.persistShardFunc)(item), fileName)
+	}
+	return filepath.Join(l.opts.persistRootPath, fileName)
+}
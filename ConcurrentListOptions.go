@@ -1,22 +1,63 @@
 package concurrentList
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// How many errors are collected by default if no errorHandler is set
+const defaultErrorBufferSize = 100
 
 type ConcurrentListOption interface {
 	apply(*concurrentListOptions)
 }
 
 type concurrentListOptions struct {
-	lessFunc            *func(i, j interface{}) bool
-	persistChanges      bool
-	persistRootPath     string
-	persistItemType     interface{}
-	persistFileNameFunc *func(i interface{}) string
-	persistErrorHandler *func(error)
-	ttlEnabled          bool
-	ttlDuration         *time.Duration
-	ttlCheckInverval    *time.Duration
-	ttlFunc             *func(i interface{}) time.Time
+	lessFunc                *func(i, j interface{}) bool
+	priorityFunc            *func(i interface{}) int
+	priorityDescending      bool
+	persistChanges          bool
+	persistRootPath         string
+	persistItemType         interface{}
+	persistFileNameFunc     *func(i interface{}) string
+	persistShardFunc        *func(i interface{}) string
+	persistContentHash      bool
+	persistVersioning       bool
+	persistBackends         []PersistenceBackend
+	persistErrorHandler     *func(error)
+	persistAsync            bool
+	persistReadRepair       *func(raw []byte) ([]byte, error)
+	persistQueueSize        int
+	persistLazyLoad         bool
+	persistDuplicatePolicy  DuplicatePolicy
+	persistBreakerThreshold int
+	persistBreakerCooldown  time.Duration
+	persistBreakerOnOpen    func()
+	persistTimeout          time.Duration
+	persistJSONEncoder      *func(encoder *json.Encoder)
+	ttlEnabled              bool
+	ttlDuration             *time.Duration
+	ttlCheckInverval        *time.Duration
+	ttlFunc                 *func(i interface{}) time.Time
+	ttlKeepAlive            *func(i interface{}) bool
+	lazyExpiryMaxAge        time.Duration
+	lazyExpiryFunc          *func(i interface{}) time.Time
+	errorBufferSize         int
+	onPush                  *func(item interface{})
+	onDiscard               *func(item interface{})
+	tracer                  Tracer
+	fastPath                bool
+	capacity                int
+	uniqueKeyFunc           *func(item interface{}) string
+	deferredSort            time.Duration
+	maxPersistedBytes       int64
+	wakeStrategy            WakeStrategy
+	cloneFunc               *func(item interface{}) interface{}
+	retryMaxAttempts        int
+	retryBackoff            *func(attempt int) time.Duration
+	deadLetter              *func(item interface{}, err error)
 }
 
 type funcConcurrentListOption struct {
@@ -34,18 +75,68 @@ func newFuncConcurrentListOption(f func(*concurrentListOptions)) *funcConcurrent
 // WithSorting will automatically sort the contents of the list everytime
 // an item is pushed according to the passed function
 // WithSorting can also be used to create a priorityQueue
+// With WithPersistence every file holds the item along with the order it was pushed in, so equal items keep their order
+// when the list is reconstructed
 func WithSorting(lessFunc func(i, j interface{}) bool) ConcurrentListOption {
 	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
 		o.lessFunc = &lessFunc
 	})
 }
 
+// WithComparator works like WithSorting, but with a three-way comparison (like strings.Compare): cmp returns
+// a negative number if a comes before b, a positive number if b comes before a and 0 if they are equal.
+// Equal items keep the order they were pushed in
+func WithComparator(cmp func(a, b interface{}) int) ConcurrentListOption {
+	return WithSorting(func(i, j interface{}) bool {
+		return cmp(i, j) < 0
+	})
+}
+
+// WithAging sorts the list by an effective priority which grows with the time an item spent in the list, so items
+// with a low priority are consumed eventually even if items with a high priority keep arriving (see WithSorting).
+// The item with the highest effective priority is returned first:
+//
+//	priority(item) + agingRate * (seconds since pushTime(item))
+//
+// As all items age at the same rate, their order does not change while they are in the list: the list is sorted
+// once when items are added. It replaces a lessFunc passed to WithSorting
+func WithAging(priority func(item interface{}) float64, agingRate float64, pushTime func(item interface{}) time.Time) ConcurrentListOption {
+	// The effective priority minus agingRate * (seconds since the unix epoch), which is the same for every point in time
+	score := func(item interface{}) float64 {
+		return priority(item) - agingRate*float64(pushTime(item).UnixNano())/float64(time.Second)
+	}
+	return WithSorting(func(i, j interface{}) bool {
+		return score(i) > score(j)
+	})
+}
+
+// WithUniqueness makes sure the list holds at most one item per key (determined by keyFunc): pushed items whose key is
+// in the list already are skipped (they still count as accepted for TryPush). Lists which are reconstructed from persistence
+// keep the first item per key (in the order of the list) and delete the files of the others.
+// Pushing checks the keys of all items of the list. ATTENTION: ReplaceAll and InsertAt do not check keys
+func WithUniqueness(keyFunc func(item interface{}) string) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.uniqueKeyFunc = &keyFunc
+	})
+}
+
+// WithDeferredSort postpones sorting the list (see WithSorting) after a push until no items were pushed for the quiet duration,
+// so a burst of pushes is sorted once instead of after every push. Reading the list before (e.g. Peek, GetNext, GetWithFilter)
+// sorts it right away, results are the same as without WithDeferredSort. PushReport always sorts right away
+func WithDeferredSort(quiet time.Duration) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.deferredSort = quiet
+	})
+}
+
 // WithPersistence adds persistence in terms of "one file per item in the list" on the harddrive
 // Whenever anything is added or removed a file with the json-marshaled contents is put into or removed from a directory.
-// The caller needs to make sure that the directory of rootPath exists and is writable by the process
+// Items implementing encoding.BinaryMarshaler and encoding.BinaryUnmarshaler (with a pointer receiver) are marshaled with those instead of json
+// The directory of rootPath is created if it does not exist yet, it needs to be writable by the process
 // fileNameFunc determines the fileName of every item-file
 // itemType is required so the types can be reconstructed from the contents of the rootFolder
 // an optional errorHandler can be passed if the caller wants to process perstisting errors
+// (otherwise they can be retrieved with Errors())
 func WithPersistence(rootPath string, itemType interface{}, fileNameFunc func(i interface{}) string, errorHandler ...func(error)) ConcurrentListOption {
 	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
 		o.persistChanges = true
@@ -59,17 +150,310 @@ func WithPersistence(rootPath string, itemType interface{}, fileNameFunc func(i
 	})
 }
 
+// WithPersistenceBackend persists all items in backend (additionally to the directory of WithPersistence, if used):
+// every pushed item is stored in and every removed item is deleted from all backends, in the order the options are passed.
+// It can be passed multiple times (e.g. for mirroring the list). The list is reconstructed from the directory of WithPersistence
+// or, without it, from the first backend. WithAsyncPersistence and WithPersistenceCircuitBreaker apply to backends as well.
+// Errors of backends are passed to the errorHandler of WithPersistence (if any)
+func WithPersistenceBackend(backend PersistenceBackend) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.persistChanges = true
+		o.persistBackends = append(o.persistBackends, backend)
+	})
+}
+
+// WithShardedPersistence works like WithPersistence, but puts the file of every item into a subdirectory of rootPath
+// which is determined by shardFunc (e.g. the first two characters of a hash). This keeps the number of files per directory
+// manageable for large lists. Subdirectories are created as needed
+func WithShardedPersistence(rootPath string, itemType interface{}, shardFunc func(i interface{}) string, fileNameFunc func(i interface{}) string, errorHandler ...func(error)) ConcurrentListOption {
+	persistence := WithPersistence(rootPath, itemType, fileNameFunc, errorHandler...)
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		persistence.apply(o)
+		o.persistShardFunc = &shardFunc
+	})
+}
+
+// WithJSONEncoderOptions configures how the files of WithPersistence are marshaled (e.g. SetEscapeHTML(false) or SetIndent
+// for readable files): configure is called with a fresh json.Encoder for every item. The files are still read with json.Unmarshal.
+// ATTENTION: Items implementing encoding.BinaryMarshaler are not affected, WithContentHashPersistence names files after the default encoding
+func WithJSONEncoderOptions(configure func(encoder *json.Encoder)) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.persistJSONEncoder = &configure
+	})
+}
+
+// WithContentHashPersistence works like WithPersistence, but names every file after the SHA-256 of its marshaled contents
+// so no fileNameFunc is required. Identical items share a single file, which is only deleted once the last of them is removed.
+// ATTENTION: Identical items are deduplicated when the list is reconstructed from the rootPath (only one of them is loaded)
+func WithContentHashPersistence(rootPath string, itemType interface{}, errorHandler ...func(error)) ConcurrentListOption {
+	persistence := WithPersistence(rootPath, itemType, func(item interface{}) string {
+		marshaled, err := persistenceMarshal(item, nil)
+		if err != nil {
+			// Marshaling the contents of the file will fail with the same error
+			return ""
+		}
+		hash := sha256.Sum256(marshaled)
+		return hex.EncodeToString(hash[:])
+	}, errorHandler...)
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		persistence.apply(o)
+		o.persistContentHash = true
+	})
+}
+
+// WithVersioning stores a version along with every item in its file of WithPersistence (e.g. for multiple processes sharing a directory):
+// every write of a file increments the version of the record which is on disk, so the last writer always supersedes the others.
+// A file which was written by another list since this list wrote or read it is not deleted: ErrVersionConflict is passed to the
+// errorHandler instead. Files which were written without WithVersioning are read with version 0.
+// ATTENTION: Versioning is not atomic across processes (the version is read before the file is written) and
+// WithLazyPersistenceLoad has no effect
+func WithVersioning() ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.persistVersioning = true
+	})
+}
+
+// WithReadRepair allows upgrading files of WithPersistence which cannot be reconstructed (e.g. files in an older format)
+// The repair func is called with the contents of every file which fails to load and returns the contents in the current format.
+// Successfully repaired files are rewritten. If the repair func returns an error, loading the list fails with that error
+func WithReadRepair(repair func(raw []byte) ([]byte, error)) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.persistReadRepair = &repair
+	})
+}
+
+// WithLazyPersistenceLoad speeds up creating a list with many persisted items (see WithPersistence):
+// when the list is created only the files are indexed, every item is reconstructed from its file once it is needed.
+// Consuming items (e.g. Shift, Peek, GetNext) only reconstructs the first item, all operations which look at
+// more items (e.g. GetWithFilter, TopK, the ttl checks of WithTTL) reconstruct all remaining items at once.
+// Items which cannot be reconstructed are dropped from the list and the error is passed to the errorHandler.
+// ATTENTION: Has no effect in combination with WithSorting, sorting requires all items to be reconstructed right away
+func WithLazyPersistenceLoad() ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.persistLazyLoad = true
+	})
+}
+
+// DuplicatePolicy determines how multiple persisted files which hold the same item are handled when the list is reconstructed (see WithLoadDuplicatePolicy)
+type DuplicatePolicy int
+
+const (
+	// DuplicatesKeepAll loads every file as a separate item (default)
+	DuplicatesKeepAll DuplicatePolicy = iota
+	// DuplicatesKeepFirst only loads the first file (in the order of their names) and deletes all others
+	DuplicatesKeepFirst
+	// DuplicatesError fails loading the list with ErrDuplicateItem
+	DuplicatesError
+)
+
+// WithLoadDuplicatePolicy determines what happens if multiple files of WithPersistence hold the same item when the list is reconstructed
+// (e.g. after a change of the fileNameFunc). Files hold the same item if the path of the reconstructed items (according to fileNameFunc) is the same.
+// ATTENTION: Detecting duplicates requires reconstructing all items, WithLazyPersistenceLoad has no effect if a policy other than DuplicatesKeepAll is used
+func WithLoadDuplicatePolicy(policy DuplicatePolicy) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.persistDuplicatePolicy = policy
+	})
+}
+
+// WithAsyncPersistence moves writing and deleting the files of WithPersistence out of the critical section of the list:
+// Push and all removals only enqueue the operation, a single background worker performs them in the same order.
+// This way producers and consumers do not wait for disk I/O. FlushPersistence waits until all pending operations are done.
+// The list never waits for the worker: if queueSize operations are pending already, further operations are dropped and
+// ErrPersistenceQueueFull is passed to the errorHandler (RebuildPersistence restores the directory from memory afterwards).
+// ATTENTION: Has no effect without WithPersistence. Pending operations are lost if the process exits
+func WithAsyncPersistence(queueSize int) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.persistAsync = true
+		o.persistQueueSize = queueSize
+	})
+}
+
+// WithPersistenceTimeout stops waiting for a persistence operation (writing or deleting a file of WithPersistence,
+// including all backends of WithPersistenceBackend) after timeout (e.g. a hanging network filesystem):
+// ErrPersistenceTimeout is passed to the errorHandler and the list continues. An operation which timed out keeps running
+// in the background, operations are never performed concurrently or out of order.
+// ATTENTION: Until the operation which timed out is done, all further operations are skipped (and fail with ErrPersistenceTimeout
+// right away), so their files are missing or left behind. RebuildPersistence restores the directory from memory afterwards
+func WithPersistenceTimeout(timeout time.Duration) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.persistTimeout = timeout
+	})
+}
+
+// WithPersistenceCircuitBreaker stops attempting persistence operations of WithPersistence for cooldown once threshold
+// consecutive operations failed (e.g. because the disk is full), so the errorHandler is not flooded with the same error.
+// onOpen (optional, may be nil) is called once every time the breaker opens. It must not use the list, as it may be called while the list is locked.
+// After the cooldown operations are attempted again.
+// ATTENTION: Items which are added or removed while the breaker is open are not written or deleted on disk
+func WithPersistenceCircuitBreaker(threshold int, cooldown time.Duration, onOpen func()) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.persistBreakerThreshold = threshold
+		o.persistBreakerCooldown = cooldown
+		o.persistBreakerOnOpen = onOpen
+	})
+}
+
 // WithTTL adds a time-to-live to every item in the list
-// ATTENTION: Currently the user is required to add an attribute to every item which contains the timestamp of when it is added
+// ATTENTION: The user is required to add an attribute to every item which contains the timestamp of when it is added (see WithAutoTTL otherwise)
 // Required parameters are
 // - ttl: 						how long will an item linger in the list until it is deleted automatically
-// - ttlCheckInterval: 			in which interval are the ttl's of the items checked
+// - ttlCheckInterval: 			in which interval are the ttl's of the items checked at least
 // - ttlFunc: 					this func is called for every item in order to extract the timestamp of when it was added
-func WithTTL(ttl time.Duration, ttlCheckInterval time.Duration, ttlFunc func(item interface{}) time.Time) ConcurrentListOption {
+// Additionally items are checked once the next item is about to expire, an empty list is not checked until items are added.
+// Optionally a keepAlive func can be passed: expired items for which it returns true (e.g. because they are "pinned")
+// survive the current check and are checked again in the next interval. It must not use the list
+func WithTTL(ttl time.Duration, ttlCheckInterval time.Duration, ttlFunc func(item interface{}) time.Time, keepAlive ...func(item interface{}) bool) ConcurrentListOption {
 	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
 		o.ttlEnabled = true
 		o.ttlDuration = &ttl
 		o.ttlFunc = &ttlFunc
 		o.ttlCheckInverval = &ttlCheckInterval
+
+		if len(keepAlive) == 1 {
+			o.ttlKeepAlive = &keepAlive[0]
+		}
+	})
+}
+
+// WithAutoTTL adds a time-to-live to every item in the list
+// In contrast to WithTTL the list keeps track of when each item was added, so no attribute is required
+// Items which are reconstructed from persistence are considered added at the modification time of their file
+// Required parameters are
+// - ttl: 						how long will an item linger in the list until it is deleted automatically
+// - ttlCheckInterval: 			in which interval are the ttl's of the items checked at least (see WithTTL)
+func WithAutoTTL(ttl time.Duration, ttlCheckInterval time.Duration) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.ttlEnabled = true
+		o.ttlDuration = &ttl
+		o.ttlFunc = nil
+		o.ttlCheckInverval = &ttlCheckInterval
+	})
+}
+
+// WithErrorBufferSize sets how many errors are kept for retrieval with Errors() if no errorHandler is set
+// When the buffer is full the oldest error is discarded. A size of 0 disables collecting errors (default: 100)
+func WithErrorBufferSize(size int) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.errorBufferSize = size
+	})
+}
+
+// WithOnPush registers a hook which is called for every pushed item after it was added (and persisted)
+// The hook is called synchronously by Push but without holding the lock of the list
+func WithOnPush(onPush func(item interface{})) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.onPush = &onPush
+	})
+}
+
+// WithOnDiscard registers a hook which is called for every item which is removed in bulk or dropped by the list instead of
+// being handed to a single consumer: items removed by Clear, Drain or DrainInto, replaced by ReplaceAll, given up on by
+// GetNextAcceptable and items evicted by WithTTL or WithLazyExpiry (e.g. for releasing resources held by the items). Items which are consumed one by one (e.g. by
+// Shift or GetNext) are not passed.
+// The hook is called while the list is locked. It must not use the list
+func WithOnDiscard(onDiscard func(item interface{})) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.onDiscard = &onDiscard
+	})
+}
+
+// WithTracer registers a tracer which is notified before and after every GetNext
+// (e.g. for recording how long consumers are blocked)
+func WithTracer(tracer Tracer) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.tracer = tracer
+	})
+}
+
+// WithLazyExpiry drops items which are older than maxAge when they are about to be consumed or looked at (Shift, GetNext, Peek)
+// instead of checking them periodically in the background like WithTTL. Expired items are deleted and skipped.
+// timeFunc is called in order to extract the timestamp of when an item was added
+func WithLazyExpiry(maxAge time.Duration, timeFunc func(item interface{}) time.Time) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.lazyExpiryMaxAge = maxAge
+		o.lazyExpiryFunc = &timeFunc
+	})
+}
+
+// WithFastPath lowers the overhead of consumers which have to wait for an item (GetNext and friends)
+// by reusing the internal bookkeeping of finished waits instead of allocating it for every wait.
+// Ordering and cancellation behave exactly the same. Best suited for a single or a few consumers,
+// as the bookkeeping for the largest number of concurrently waiting consumers is kept around
+func WithFastPath() ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.fastPath = true
+	})
+}
+
+// WakeStrategy determines which waiting consumers are woken up when items are added (see WithWakeStrategy)
+type WakeStrategy int
+
+const (
+	// WakeBroadcast wakes up one waiting consumer per added item (in the order they started waiting) and additionally all
+	// other waiting consumers, which check the list again and wait again (at the end of the queue) if they do not get an item.
+	// Safe against missed wake-ups, but costs a wake-up of every waiting consumer for each push (default)
+	WakeBroadcast WakeStrategy = iota
+	// WakeSignal wakes up exactly one waiting consumer per added item, in the order they started waiting.
+	// A consumer whose context expires right after it was woken up passes the wake-up on, so no item is left behind.
+	// Scales with the number of consumers, but relies on every wake-up being passed on correctly
+	WakeSignal
+)
+
+// WithMaxPersistedBytes limits the total size of the files of WithPersistence (measured by the size of the marshaled items):
+// if a pushed item does not fit, the oldest items are dropped until it does (see WithOnDiscard). If the items of a push
+// do not fit without dropping items of the same push (e.g. items larger than max), Push rejects all of them and passes
+// ErrListFull to the errorHandler, TryPush only accepts the items in front of the first one which does not fit and returns ErrListFull.
+// Every pushed item is marshaled an additional time in order to determine its size.
+// ATTENTION: ReplaceAll, InsertAt and items which are reconstructed from persistence are not limited
+func WithMaxPersistedBytes(max int64) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.maxPersistedBytes = max
+	})
+}
+
+// WithWakeStrategy determines which waiting consumers (GetNext and friends) are woken up when items are added.
+// WakeBroadcast (default) lets all consumers re-check the list on every push, so none of them can miss an item,
+// at the cost of a thundering herd with many consumers. WakeSignal scales with the number of consumers instead
+func WithWakeStrategy(strategy WakeStrategy) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.wakeStrategy = strategy
+	})
+}
+
+// WithDeepCopy isolates the list from changes to items after they were added (e.g. structs containing slices or maps):
+// Push (and all other ways of adding items) store clone(item) instead of the passed item.
+// Cloning happens before the lock of the list is acquired. The items which are returned by the list are not copied
+func WithDeepCopy(clone func(item interface{}) interface{}) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.cloneFunc = &clone
+	})
+}
+
+// WithCapacity limits the number of items in the list: Push (and PushSorted, PushWithPriority) blocks until all items fit,
+// TryPush adds as many items as fit without blocking and InsertAt returns ErrListFull if the list is full.
+// Pushing more items than the capacity at once waits until the list is empty and then exceeds the capacity.
+// ATTENTION: ReplaceAll and items which are reconstructed from persistence are not limited
+func WithCapacity(capacity int) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.capacity = capacity
+	})
+}
+
+// WithRetryPolicy limits how often ProcessNext retries an item: after a failed attempt the item keeps its position,
+// but ProcessNext skips it until backoff(attempt) passed (attempt starts at 1). Once maxAttempts attempts failed,
+// the item is removed from the list and passed to the callback of WithDeadLetter (if any).
+// ATTENTION: Only ProcessNext honors the backoff, all other methods (e.g. GetNext) still see the item
+func WithRetryPolicy(maxAttempts int, backoff func(attempt int) time.Duration) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.retryMaxAttempts = maxAttempts
+		o.retryBackoff = &backoff
+	})
+}
+
+// WithDeadLetter registers a callback for items which are dropped because they exhausted the attempts of WithRetryPolicy,
+// along with the error of the last attempt. The callback is called while the list is locked. It must not use the list
+func WithDeadLetter(deadLetter func(item interface{}, err error)) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.deadLetter = &deadLetter
 	})
 }
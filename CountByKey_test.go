@@ -0,0 +1,17 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountByKey(t *testing.T) {
+	list := NewConcurrentList()
+	category := func(item interface{}) string { return item.(string)[:1] }
+	require.Empty(t, list.CountByKey(category))
+
+	list.Push("a1", "b1", "a2", "c1", "a3", "b2")
+	require.Equal(t, map[string]int{"a": 3, "b": 2, "c": 1}, list.CountByKey(category))
+	require.Equal(t, 6, list.Length())
+}
@@ -0,0 +1,46 @@
+package concurrentList
+
+import "sync"
+
+// Cursor pages through a snapshot of a ConcurrentList which is taken when the cursor is created.
+// Changes to the list after that point are not reflected, so a single paging session never
+// skips or duplicates items
+type Cursor struct {
+	// Hold snapshot
+	data []interface{}
+
+	// Position of the next item to be returned
+	position int
+
+	// Protect position
+	lock *sync.Mutex
+}
+
+// NewCursor creates a cursor on a snapshot of the current contents of the list
+func (l *ConcurrentList) NewCursor() *Cursor {
+	return &Cursor{
+		data: l.snapshot(),
+		lock: new(sync.Mutex),
+	}
+}
+
+// Next returns the next page of (at most) n items of the snapshot.
+// Returns an empty slice once the snapshot is exhausted. Every page is a copy, so it can be modified freely
+func (c *Cursor) Next(n int) []interface{} {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if n < 0 {
+		n = 0
+	}
+
+	end := c.position + n
+	if end > len(c.data) {
+		end = len(c.data)
+	}
+
+	page := make([]interface{}, end-c.position)
+	copy(page, c.data[c.position:end])
+	c.position = end
+	return page
+}
@@ -0,0 +1,36 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	list := NewConcurrentList()
+	for i := 0; i < 10; i++ {
+		list.Push(i)
+	}
+
+	cursor := list.NewCursor()
+
+	// Mutate the list after the cursor was created
+	_, err := list.Shift()
+	require.NoError(t, err)
+	list.Push(10)
+	list.DeleteWithFilter(func(item interface{}) bool {
+		return item.(int)%2 == 0
+	})
+
+	// Appending to a page does not overwrite the items of the next one
+	page := cursor.Next(4)
+	require.Equal(t, []interface{}{0, 1, 2, 3}, page)
+	_ = append(page, "appended")
+	require.Equal(t, []interface{}{4, 5, 6, 7}, cursor.Next(4))
+	require.Equal(t, []interface{}{8, 9}, cursor.Next(4))
+	require.Empty(t, cursor.Next(4))
+
+	// A new cursor sees the current contents
+	cursor = list.NewCursor()
+	require.Equal(t, []interface{}{1, 3, 5, 7, 9}, cursor.Next(10))
+}
@@ -0,0 +1,8 @@
+//go:build debug
+// +build debug
+
+package concurrentList
+
+// Additional checks which are too expensive for production (e.g. validating the input of PushSorted) are enabled
+// by building with the debug tag (go build -tags debug)
+const debug = true
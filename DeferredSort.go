@@ -0,0 +1,30 @@
+package concurrentList
+
+import "time"
+
+// internal helper function for sorting the list once no items were pushed for the quiet duration of WithDeferredSort.
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) deferSort() {
+	if l.opts.lessFunc == nil {
+		return
+	}
+
+	l.sortPending = true
+	if l.sortTimer == nil {
+		l.sortTimer = time.AfterFunc(l.opts.deferredSort, func() {
+			l.lock.Lock()
+			defer l.lock.Unlock()
+			l.applyDeferredSort()
+		})
+		return
+	}
+	l.sortTimer.Reset(l.opts.deferredSort)
+}
+
+// internal helper function for sorting pushed items which were not sorted yet (see WithDeferredSort).
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) applyDeferredSort() {
+	if l.sortPending {
+		l.sortData()
+	}
+}
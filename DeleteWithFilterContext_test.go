@@ -0,0 +1,44 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteWithFilterContext(t *testing.T) {
+	length := 50
+	list := NewConcurrentList()
+	for i := 0; i < length; i++ {
+		list.Push(i)
+	}
+
+	items, err := list.DeleteWithFilterContext(context.Background(), func(item interface{}) bool {
+		return item.(int) < length/2
+	})
+	require.NoError(t, err)
+	require.Len(t, items, length/2)
+	require.Equal(t, length/2, list.Length())
+
+	// Cancel in the middle of the scan
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	checked := 0
+	items, err = list.DeleteWithFilterContext(ctx, func(item interface{}) bool {
+		checked++
+		if checked == 5 {
+			cancel()
+		}
+		return true
+	})
+	require.Equal(t, context.Canceled, err)
+	require.Nil(t, items)
+	require.Equal(t, 5, checked)
+
+	// The list is unchanged
+	require.Equal(t, length/2, list.Length())
+	first, err := list.Peek()
+	require.NoError(t, err)
+	require.Equal(t, length/2, first)
+}
@@ -0,0 +1,70 @@
+package concurrentList
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Number of errors which are listed by Diagnostics
+const diagnosticsErrors = 5
+
+// Diagnostics returns a human readable, multi-line report of the state of the list (e.g. for logging it when a consumer
+// seems to be stuck): length, capacity, waiting consumers, when items were last pushed and shifted, persistence, ttl (along with
+// when the next loaded item expires) and the most recent collected errors. The format is meant for humans and may change, use Stats for processing the values.
+// Errors are not removed (in contrast to Errors())
+func (l *ConcurrentList) Diagnostics() string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	report := &strings.Builder{}
+	fmt.Fprintf(report, "length: %d\n", len(l.data))
+	if l.opts.capacity > 0 {
+		fmt.Fprintf(report, "capacity: %d\n", l.opts.capacity)
+	} else {
+		fmt.Fprintf(report, "capacity: unbounded\n")
+	}
+	fmt.Fprintf(report, "waiting consumers: %d (%d woken up)\n", len(l.waiters), l.wokenWaiters)
+	fmt.Fprintf(report, "last push: %s\n", diagnosticsTime(l.lastPushedAt))
+	fmt.Fprintf(report, "last shift: %s\n", diagnosticsTime(l.lastShiftedAt))
+	fmt.Fprintf(report, "closed: %t\n", l.closed)
+
+	switch {
+	case !l.opts.persistChanges:
+		fmt.Fprintf(report, "persistence: disabled\n")
+	case l.PersistenceHealthy():
+		fmt.Fprintf(report, "persistence: healthy\n")
+	default:
+		fmt.Fprintf(report, "persistence: failing\n")
+	}
+
+	// Items which are not loaded yet are not loaded for the report (see WithLazyPersistenceLoad)
+	if !l.opts.ttlEnabled {
+		fmt.Fprintf(report, "ttl: disabled\n")
+	} else if next, ok := l.nextLoadedExpiry(); ok {
+		fmt.Fprintf(report, "ttl: %s (next expiry in %s)\n", *l.opts.ttlDuration, time.Until(next).Round(time.Millisecond))
+	} else {
+		fmt.Fprintf(report, "ttl: %s (no item expires)\n", *l.opts.ttlDuration)
+	}
+
+	l.errorsLock.Lock()
+	errs := l.errors
+	if len(errs) > diagnosticsErrors {
+		errs = errs[len(errs)-diagnosticsErrors:]
+	}
+	fmt.Fprintf(report, "recent errors: %d\n", len(errs))
+	for _, err := range errs {
+		fmt.Fprintf(report, "  %s\n", err)
+	}
+	l.errorsLock.Unlock()
+
+	return report.String()
+}
+
+// internal helper function for formatting a point in time of Diagnostics
+func diagnosticsTime(at time.Time) string {
+	if at.IsZero() {
+		return "never"
+	}
+	return fmt.Sprintf("%s (%s ago)", at.Format(time.RFC3339Nano), time.Since(at).Round(time.Millisecond))
+}
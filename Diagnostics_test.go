@@ -0,0 +1,39 @@
+package concurrentList
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	list := NewConcurrentList()
+	report := list.Diagnostics()
+	require.Contains(t, report, "length: 0\n")
+	require.Contains(t, report, "capacity: unbounded\n")
+	require.Contains(t, report, "last push: never\n")
+	require.Contains(t, report, "last shift: never\n")
+	require.Contains(t, report, "persistence: disabled\n")
+	require.Contains(t, report, "ttl: disabled\n")
+	require.Contains(t, report, "recent errors: 0\n")
+
+	list = NewConcurrentList(WithCapacity(10), WithAutoTTL(time.Hour, time.Hour))
+	defer list.Close()
+	list.Push(1, 2)
+	_, err := list.Shift()
+	require.NoError(t, err)
+	list.handleError(errors.New("something failed"))
+
+	report = list.Diagnostics()
+	require.Contains(t, report, "length: 1\n")
+	require.Contains(t, report, "capacity: 10\n")
+	require.Contains(t, report, "waiting consumers: 0")
+	require.NotContains(t, report, "never")
+	require.Contains(t, report, "ttl: 1h0m0s (next expiry in ")
+	require.Contains(t, report, "recent errors: 1\n  something failed\n")
+
+	// Errors are kept
+	require.Len(t, list.Errors(), 1)
+}
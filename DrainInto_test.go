@@ -0,0 +1,45 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainInto(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestDrainInto")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	list.Push(1, 2, 3)
+
+	// The buffer is reused if its capacity suffices
+	buffer := make([]interface{}, 0, 10)
+	drained := list.DrainInto(buffer)
+	require.Equal(t, []interface{}{1, 2, 3}, drained)
+	require.True(t, &buffer[:1][0] == &drained[0])
+	require.Equal(t, 0, list.Length())
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 0)
+	require.NoError(t, list.checkInvariants())
+
+	// Items are appended to what is already in the buffer
+	list.Push(4, 5)
+	drained = list.DrainInto(drained[:1])
+	require.Equal(t, []interface{}{1, 4, 5}, drained)
+	require.True(t, &buffer[:1][0] == &drained[0])
+
+	// The buffer grows if needed
+	list.Push(6)
+	require.Equal(t, []interface{}{6}, list.DrainInto(nil))
+	require.Empty(t, list.DrainInto(nil))
+}
@@ -0,0 +1,72 @@
+package concurrentList
+
+import (
+	"context"
+	"time"
+)
+
+// Entry is an item of the list along with metadata which is kept alongside it (e.g. a trace id or an attempt count),
+// so bookkeeping does not need to be part of the item itself
+type Entry struct {
+	Value interface{}
+
+	// Passed to PushEntry and returned unchanged. ATTENTION: Metadata is not persisted, reconstructed items have none
+	Metadata map[string]interface{}
+
+	// When the item was added to the list (only set for entries which are returned)
+	PushedAt time.Time
+}
+
+// PushEntry appends the values of the passed entries just like Push and keeps their metadata alongside them.
+// Sorting, filtering and all other methods only see the values. PushedAt of the entries is ignored
+func (l *ConcurrentList) PushEntry(entries ...Entry) {
+	items := make([]interface{}, len(entries))
+	metadata := make([]map[string]interface{}, len(entries))
+	for i, entry := range entries {
+		items[i] = entry.Value
+		metadata[i] = entry.Metadata
+	}
+	l.push(items, pushOptions{metadata: metadata})
+}
+
+// ShiftEntry works like Shift, but returns the item along with its metadata (see PushEntry)
+func (l *ConcurrentList) ShiftEntry() (Entry, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	item, err := l.shiftItem()
+	if err != nil {
+		return Entry{}, err
+	}
+	return item.entry(), nil
+}
+
+// GetNextEntry works like GetNext, but returns the item along with its metadata (see PushEntry)
+func (l *ConcurrentList) GetNextEntry(ctx context.Context) (entry Entry, err error) {
+	if l.opts.tracer != nil {
+		started := l.traceGetNextStart(ctx)
+		defer func() { l.traceGetNextEnd(ctx, started, err) }()
+	}
+
+	if ctx.Err() != nil {
+		return Entry{}, ErrEmptyList
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err := l.waitForItem(ctx); err != nil {
+		return Entry{}, err
+	}
+
+	item, err := l.shiftItem()
+	if err != nil {
+		return Entry{}, err
+	}
+	return item.entry(), nil
+}
+
+// entry returns the value of the item along with its bookkeeping
+func (i *listItem) entry() Entry {
+	return Entry{Value: i.value, Metadata: i.metadata, PushedAt: i.pushedAt}
+}
@@ -0,0 +1,42 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntry(t *testing.T) {
+	list := NewOrderedList()
+	before := time.Now()
+
+	list.PushEntry(Entry{Value: 2, Metadata: map[string]interface{}{"traceID": "b", "attempt": 1}}, Entry{Value: 1, Metadata: map[string]interface{}{"traceID": "a"}})
+	list.Push(3)
+
+	// Sorting only sees the values
+	entry, err := list.GetNextEntry(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, entry.Value)
+	require.Equal(t, map[string]interface{}{"traceID": "a"}, entry.Metadata)
+	require.False(t, entry.PushedAt.Before(before))
+
+	entry, err = list.ShiftEntry()
+	require.NoError(t, err)
+	require.Equal(t, 2, entry.Value)
+	require.Equal(t, map[string]interface{}{"traceID": "b", "attempt": 1}, entry.Metadata)
+
+	// Items without metadata
+	entry, err = list.ShiftEntry()
+	require.NoError(t, err)
+	require.Equal(t, 3, entry.Value)
+	require.Nil(t, entry.Metadata)
+
+	_, err = list.ShiftEntry()
+	require.Equal(t, ErrEmptyList, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = list.GetNextEntry(ctx)
+	require.Equal(t, ErrEmptyList, err)
+}
@@ -0,0 +1,41 @@
+package concurrentList
+
+import "reflect"
+
+// Equal reports if both lists hold the same items in the same order (according to eq).
+// The items of both lists are taken at the same time (the lists are locked in a fixed order, so concurrent calls
+// with swapped lists do not deadlock) and compared afterwards, eq may use the lists
+func Equal(a, b *ConcurrentList, eq func(x, y interface{}) bool) bool {
+	if a == b {
+		return true
+	}
+
+	unlock := lockBoth(a, b)
+	x, y := a.values(), b.values()
+	unlock()
+
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if !eq(x[i], y[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// internal helper function for locking two different lists at once. The lists are always locked in the same order
+// (by their address), so concurrent calls with swapped lists do not deadlock. Returns a func for unlocking both
+func lockBoth(a, b *ConcurrentList) (unlock func()) {
+	first, second := a, b
+	if reflect.ValueOf(a).Pointer() > reflect.ValueOf(b).Pointer() {
+		first, second = b, a
+	}
+	first.lock.Lock()
+	second.lock.Lock()
+	return func() {
+		second.lock.Unlock()
+		first.lock.Unlock()
+	}
+}
@@ -0,0 +1,48 @@
+package concurrentList
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqual(t *testing.T) {
+	eq := func(x, y interface{}) bool { return x == y }
+
+	a := NewConcurrentList()
+	b := NewConcurrentList()
+	require.True(t, Equal(a, b, eq))
+
+	a.Push(1, 2, 3)
+	b.Push(1, 2, 3)
+	require.True(t, Equal(a, b, eq))
+	require.True(t, Equal(b, a, eq))
+	require.True(t, Equal(a, a, eq))
+
+	// Differing items, order and length
+	c := NewConcurrentList()
+	c.Push(1, 2, 4)
+	require.False(t, Equal(a, c, eq))
+	d := NewConcurrentList()
+	d.Push(3, 2, 1)
+	require.False(t, Equal(a, d, eq))
+	e := NewConcurrentList()
+	e.Push(1, 2)
+	require.False(t, Equal(a, e, eq))
+
+	// Concurrent comparisons with swapped lists do not deadlock
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			Equal(a, b, eq)
+		}()
+		go func() {
+			defer wg.Done()
+			Equal(b, a, eq)
+		}()
+	}
+	wg.Wait()
+}
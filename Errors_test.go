@@ -0,0 +1,57 @@
+package concurrentList
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestErrors")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	// Files cannot be written into a non-existing subdirectory
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return filepath.Join("missing", fmt.Sprint(item))
+	}), WithErrorBufferSize(3))
+
+	require.Empty(t, list.Errors())
+
+	for i := 0; i < 2; i++ {
+		list.Push(i)
+	}
+	errs := list.Errors()
+	require.Len(t, errs, 2)
+	for _, err := range errs {
+		require.True(t, os.IsNotExist(err))
+	}
+
+	// Errors are cleared once retrieved
+	require.Empty(t, list.Errors())
+
+	// Only the newest errors are kept
+	for i := 0; i < 5; i++ {
+		list.Push(i)
+	}
+	errs = list.Errors()
+	require.Len(t, errs, 3)
+	require.Contains(t, errs[0].Error(), filepath.Join("missing", "2"))
+	require.Contains(t, errs[2].Error(), filepath.Join("missing", "4"))
+
+	// Errors are not collected if there is an errorHandler
+	handled := 0
+	list = NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return filepath.Join("missing", fmt.Sprint(item))
+	}, func(err error) {
+		handled++
+	}))
+	list.Push(1)
+	require.Equal(t, 1, handled)
+	require.Empty(t, list.Errors())
+}
@@ -0,0 +1,25 @@
+package concurrentList
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvictedCount(t *testing.T) {
+	list := NewConcurrentList(WithAutoTTL(20*time.Millisecond, 5*time.Millisecond))
+	defer func() {
+		require.NoError(t, list.Close())
+	}()
+	require.Equal(t, int64(0), list.EvictedCount())
+
+	list.Push(1, 2, 3)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int64(3), list.EvictedCount())
+
+	list.Push(4, 5)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int64(5), list.EvictedCount())
+	require.Equal(t, 0, list.Length())
+}
@@ -0,0 +1,18 @@
+package concurrentList
+
+// Filter creates a new list (with the passed options) which holds all items of l that match a predicate,
+// in the order of l. l itself is not changed. The items are taken from a snapshot of l, so the predicate may use l
+func Filter(l *ConcurrentList, predicate func(item interface{}) bool, opts ...ConcurrentListOption) *ConcurrentList {
+	matching := []interface{}{}
+	for _, item := range l.snapshot() {
+		if predicate(item) {
+			matching = append(matching, item)
+		}
+	}
+
+	filtered := NewConcurrentList(opts...)
+	if len(matching) > 0 {
+		filtered.Push(matching...)
+	}
+	return filtered
+}
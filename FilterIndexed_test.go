@@ -0,0 +1,27 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWithFilterIndexed(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push("a", "b", "c", "d")
+
+	// Everything after the head
+	items := list.GetWithFilterIndexed(func(index int, item interface{}) bool { return index > 0 })
+	require.Equal(t, []interface{}{"b", "c", "d"}, items)
+	require.Equal(t, 4, list.Length())
+}
+
+func TestDeleteWithFilterIndexed(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push("a", "b", "c", "d", "e")
+
+	// Indices refer to the list before anything was removed
+	removed := list.DeleteWithFilterIndexed(func(index int, item interface{}) bool { return index%2 == 0 })
+	require.Equal(t, []interface{}{"a", "c", "e"}, removed)
+	require.Equal(t, []interface{}{"b", "d"}, list.GetWithFilter(func(item interface{}) bool { return true }))
+}
@@ -0,0 +1,29 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push(1, 2, 3, 4, 5, 6)
+
+	even := Filter(list, func(item interface{}) bool {
+		return item.(int)%2 == 0
+	})
+	require.Equal(t, []interface{}{2, 4, 6}, even.snapshot())
+	require.Equal(t, []interface{}{1, 2, 3, 4, 5, 6}, list.snapshot())
+
+	// The new list uses the passed options
+	descending := Filter(list, func(item interface{}) bool {
+		return item.(int) > 3
+	}, WithSorting(func(i, j interface{}) bool {
+		return i.(int) > j.(int)
+	}))
+	require.Equal(t, []interface{}{6, 5, 4}, descending.snapshot())
+	require.True(t, descending.IsSorted())
+
+	require.Equal(t, 0, Filter(list, func(item interface{}) bool { return false }).Length())
+}
@@ -0,0 +1,81 @@
+package concurrentList
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNextAcceptable(t *testing.T) {
+	list := NewConcurrentList()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ready := int32(0)
+	accept := func(item interface{}) (bool, bool) {
+		if item.(string) == "drop" {
+			return false, true
+		}
+		if item.(string) == "other" {
+			return true, false
+		}
+		return atomic.LoadInt32(&ready) == 1, false
+	}
+
+	// The first two items are deferred until their dependency is ready
+	list.Push("first", "second")
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		atomic.StoreInt32(&ready, 1)
+		list.Push("third")
+	}()
+
+	item, err := list.GetNextAcceptable(ctx, accept)
+	require.NoError(t, err)
+	require.Equal(t, "first", item)
+	item, err = list.GetNextAcceptable(ctx, accept)
+	require.NoError(t, err)
+	require.Equal(t, "second", item)
+	item, err = list.GetNextAcceptable(ctx, accept)
+	require.NoError(t, err)
+	require.Equal(t, "third", item)
+
+	// Deferred items are moved behind the others, dropped items are removed
+	atomic.StoreInt32(&ready, 0)
+	list.Push("deferred", "drop", "other", "last")
+	item, err = list.GetNextAcceptable(ctx, accept)
+	require.NoError(t, err)
+	require.Equal(t, "other", item)
+	require.Equal(t, []interface{}{"last", "deferred"}, list.GetWithFilter(func(item interface{}) bool { return true }))
+
+	// Nothing is acceptable until the context expires
+	timeoutCtx, cancelTimeout := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancelTimeout()
+	_, err = list.GetNextAcceptable(timeoutCtx, accept)
+	require.Equal(t, ErrEmptyList, err)
+	require.Equal(t, 2, list.Length())
+}
+
+func TestGetNextAcceptableDiscardAndStats(t *testing.T) {
+	discarded := []interface{}{}
+	list := NewConcurrentList(WithOnDiscard(func(item interface{}) {
+		discarded = append(discarded, item)
+	}))
+
+	list.Push("drop", "deferred", "accepted")
+	item, err := list.GetNextAcceptable(context.Background(), func(item interface{}) (bool, bool) {
+		return item.(string) == "accepted", item.(string) == "drop"
+	})
+	require.NoError(t, err)
+	require.Equal(t, "accepted", item)
+
+	// Items which are given up on are discarded, the accepted one is counted as shifted
+	require.Equal(t, []interface{}{"drop"}, discarded)
+	stats := list.Stats()
+	require.Equal(t, int64(1), stats.TotalShifted)
+	require.Equal(t, 1, stats.Length)
+	require.Equal(t, int64(2), list.TotalConsumed())
+}
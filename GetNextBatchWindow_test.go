@@ -0,0 +1,65 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNextBatchWindow(t *testing.T) {
+	list := NewConcurrentList()
+	ctx := context.Background()
+
+	// Bursts separated by gaps which are longer than the quiet period
+	go func() {
+		for _, burst := range [][]interface{}{{1, 2, 3}, {4, 5}, {6}} {
+			for _, item := range burst {
+				list.Push(item)
+				time.Sleep(5 * time.Millisecond)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}()
+
+	for _, expected := range [][]interface{}{{1, 2, 3}, {4, 5}, {6}} {
+		batch, err := list.GetNextBatchWindow(ctx, time.Second, 50*time.Millisecond, 10)
+		require.NoError(t, err)
+		require.Equal(t, expected, batch)
+	}
+
+	// maxItems
+	list.Push(1, 2, 3, 4, 5)
+	batch, err := list.GetNextBatchWindow(ctx, time.Second, 50*time.Millisecond, 3)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{1, 2, 3}, batch)
+	batch, err = list.GetNextBatchWindow(ctx, time.Second, 50*time.Millisecond, 3)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{4, 5}, batch)
+
+	// maxWait ends a batch of continuously arriving items
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			case <-time.After(5 * time.Millisecond):
+				list.Push(i)
+			}
+		}
+	}()
+	started := time.Now()
+	batch, err = list.GetNextBatchWindow(ctx, 100*time.Millisecond, 50*time.Millisecond, 1000)
+	require.NoError(t, err)
+	require.NotEmpty(t, batch)
+	require.Less(t, time.Since(started), 300*time.Millisecond)
+
+	// Expired context before the first item
+	expiredCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = NewConcurrentList().GetNextBatchWindow(expiredCtx, time.Second, 50*time.Millisecond, 10)
+	require.Equal(t, ErrEmptyList, err)
+}
@@ -0,0 +1,72 @@
+package concurrentList
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNextNMin(t *testing.T) {
+	list := NewConcurrentList()
+
+	// Items trickle in: the batch is returned once min is reached
+	go func() {
+		for i := 0; i < 5; i++ {
+			time.Sleep(5 * time.Millisecond)
+			list.Push(i)
+		}
+	}()
+	started := time.Now()
+	batch, err := list.GetNextNMin(context.Background(), 3, 3, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{0, 1, 2}, batch)
+	require.True(t, time.Since(started) < 500*time.Millisecond)
+
+	// No more than max are taken
+	time.Sleep(50 * time.Millisecond)
+	list.Push(5, 6)
+	batch, err = list.GetNextNMin(context.Background(), 1, 3, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{3, 4, 5}, batch)
+
+	// Once maxWait passed, whatever is available is returned
+	started = time.Now()
+	batch, err = list.GetNextNMin(context.Background(), 3, 10, 20*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{6}, batch)
+	require.True(t, time.Since(started) >= 20*time.Millisecond)
+
+	// At least one item is waited for
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		list.Push(7)
+	}()
+	batch, err = list.GetNextNMin(context.Background(), 3, 10, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{7}, batch)
+
+	// The context expires
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	list.Push(8)
+	_, err = list.GetNextNMin(ctx, 3, 10, time.Second)
+	require.Equal(t, ErrEmptyList, err)
+	require.Equal(t, 1, list.Length())
+}
+
+func TestGetNextNMinInvalidArgument(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push(1, 2, 3)
+
+	for _, args := range [][2]int{{0, 3}, {-1, 3}, {1, 0}, {1, -1}, {0, 0}, {3, 2}} {
+		started := time.Now()
+		batch, err := list.GetNextNMin(context.Background(), args[0], args[1], time.Second)
+		require.True(t, errors.Is(err, ErrInvalidArgument), "min %d, max %d", args[0], args[1])
+		require.Nil(t, batch)
+		require.True(t, time.Since(started) < 500*time.Millisecond)
+	}
+	require.Equal(t, 3, list.Length())
+}
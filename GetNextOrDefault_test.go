@@ -0,0 +1,18 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNextOrDefault(t *testing.T) {
+	list := NewConcurrentList()
+	require.Equal(t, "default", list.GetNextOrDefault("default"))
+
+	list.Push("first", "second")
+	require.Equal(t, "first", list.GetNextOrDefault("default"))
+	require.Equal(t, 1, list.Length())
+	require.Equal(t, "second", list.GetNextOrDefault("default"))
+	require.Equal(t, "default", list.GetNextOrDefault("default"))
+}
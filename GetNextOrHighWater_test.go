@@ -0,0 +1,43 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNextOrHighWater(t *testing.T) {
+	list := NewConcurrentList()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Drive the list over the high-water mark
+	for i := 0; i < 5; i++ {
+		list.Push(i)
+	}
+
+	expected := []bool{true, true, false, false, false}
+	for i, expectedAtHighWater := range expected {
+		item, atHighWater, err := list.GetNextOrHighWater(ctx, 3)
+		require.NoError(t, err)
+		require.Equal(t, i, item)
+		require.Equal(t, expectedAtHighWater, atHighWater, "item %d", i)
+	}
+
+	// Blocks like GetNext
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		list.Push(5)
+	}()
+	item, atHighWater, err := list.GetNextOrHighWater(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, 5, item)
+	require.False(t, atHighWater)
+
+	timeoutCtx, cancelTimeout := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancelTimeout()
+	_, _, err = list.GetNextOrHighWater(timeoutCtx, 3)
+	require.Equal(t, ErrEmptyList, err)
+}
@@ -0,0 +1,90 @@
+package concurrentList
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNextWeighted(t *testing.T) {
+	list := NewConcurrentList()
+	category := func(item interface{}) string { return item.(string)[:1] }
+	weights := map[string]int{"a": 2, "b": 1}
+
+	for i := 0; i < 300; i++ {
+		list.Push(fmt.Sprintf("a%03d", i), fmt.Sprintf("b%03d", i))
+	}
+
+	// 2:1 while both categories have items, FIFO within a category
+	counts := map[string]int{}
+	next := map[string]int{}
+	for i := 0; i < 300; i++ {
+		item, err := list.GetNextWeighted(context.Background(), category, weights)
+		require.NoError(t, err)
+		c := category(item)
+		require.Equal(t, fmt.Sprintf("%s%03d", c, next[c]), item)
+		next[c]++
+		counts[c]++
+	}
+	require.Equal(t, 200, counts["a"])
+	require.Equal(t, 100, counts["b"])
+
+	// Interleaved instead of bursts
+	list = NewConcurrentList()
+	list.Push("a1", "a2", "a3", "a4", "b1", "b2")
+	items := []interface{}{}
+	for i := 0; i < 6; i++ {
+		item, err := list.GetNextWeighted(context.Background(), category, weights)
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+	require.Equal(t, []interface{}{"a1", "b1", "a2", "a3", "b2", "a4"}, items)
+
+	// A category without items does not block the others
+	list.Push("b3")
+	item, err := list.GetNextWeighted(context.Background(), category, weights)
+	require.NoError(t, err)
+	require.Equal(t, "b3", item)
+
+	// Blocks until an item is available
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		list.Push("a5")
+	}()
+	item, err = list.GetNextWeighted(context.Background(), category, weights)
+	require.NoError(t, err)
+	require.Equal(t, "a5", item)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = list.GetNextWeighted(ctx, category, weights)
+	require.ErrorIs(t, err, ErrEmptyList)
+}
+
+func TestGetNextWeightedExpiryAndStats(t *testing.T) {
+	type test struct {
+		Category string
+		Time     time.Time
+	}
+
+	list := NewConcurrentList(WithLazyExpiry(30*time.Millisecond, func(item interface{}) time.Time {
+		return item.(test).Time
+	}))
+	list.Push(test{Category: "a", Time: time.Now()}, test{Category: "b", Time: time.Now()})
+	time.Sleep(50 * time.Millisecond)
+	fresh := test{Category: "b", Time: time.Now()}
+	list.Push(fresh)
+
+	// Expired items are never picked, taken items are counted as shifted
+	category := func(item interface{}) string { return item.(test).Category }
+	item, err := list.GetNextWeighted(context.Background(), category, nil)
+	require.NoError(t, err)
+	require.Equal(t, fresh, item)
+	require.Equal(t, 0, list.Length())
+	stats := list.Stats()
+	require.Equal(t, int64(1), stats.TotalShifted)
+	require.Equal(t, int64(2), stats.TTLEvictions)
+}
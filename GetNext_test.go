@@ -2,8 +2,12 @@ package concurrentList
 
 import (
 	"context"
+	"runtime"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetNext(t *testing.T) {
@@ -56,8 +60,8 @@ func TestGetNext(t *testing.T) {
 
 	// Wait until everything is cleaned up
 	for {
-		wait, signal := list.debug()
-		if wait > 0 && signal > 0 {
+		wait, registered := list.debug()
+		if wait > 0 && registered > 0 {
 			time.Sleep(1 * time.Millisecond)
 			continue
 		}
@@ -101,3 +105,193 @@ func producer(insertItems map[int]bool, producerIndex int, list *ConcurrentList)
 		list.Push([]int{tmp1, tmp2})
 	}
 }
+
+func TestGetNextWithExpiredContext(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	goroutinesBefore := runtime.NumGoroutine()
+	for i := 0; i < 1000; i++ {
+		_, err := list.GetNext(ctx)
+		require.Equal(t, ErrEmptyList, err)
+	}
+
+	// No routines may have been left waiting
+	wait, registered := list.debug()
+	require.Equal(t, int64(0), wait)
+	require.Equal(t, int64(0), registered)
+	require.LessOrEqual(t, runtime.NumGoroutine(), goroutinesBefore)
+	require.Equal(t, 1, list.Length())
+}
+
+func TestGetNextWakesExpiredWaiter(t *testing.T) {
+	list := NewConcurrentList()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Some waiters which never expire
+	for i := 0; i < 10; i++ {
+		go func() {
+			_, _ = list.GetNext(ctx)
+		}()
+	}
+
+	// The waiter whose context expires must return even though others are waiting as well
+	for i := 0; i < 10; i++ {
+		expiringCtx, cancelExpiring := context.WithTimeout(ctx, 10*time.Millisecond)
+		_, err := list.GetNext(expiringCtx)
+		cancelExpiring()
+		require.Equal(t, ErrEmptyList, err)
+	}
+
+	// The remaining waiters still receive items in the order they started waiting
+	list.Push(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	for {
+		if wait, _ := list.debug(); wait == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	require.Equal(t, 0, list.Length())
+}
+
+func TestGetNextCancellationStress(t *testing.T) {
+	length := 5000
+	list := NewConcurrentList()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	consumed := make(chan interface{}, length)
+
+	// Consumers which never give up
+	for i := 0; i < 4; i++ {
+		go func() {
+			for {
+				item, err := list.GetNext(ctx)
+				if err != nil {
+					return
+				}
+				consumed <- item
+			}
+		}()
+	}
+
+	// Consumers whose contexts keep expiring while items are pushed
+	expiringDone := make(chan struct{})
+	stopExpiring, cancelStopExpiring := context.WithCancel(ctx)
+	for i := 0; i < 20; i++ {
+		go func(i int) {
+			defer func() { expiringDone <- struct{}{} }()
+			for stopExpiring.Err() == nil {
+				expiringCtx, cancelExpiring := context.WithTimeout(stopExpiring, time.Duration(i*10)*time.Microsecond)
+				item, err := list.GetNext(expiringCtx)
+				cancelExpiring()
+				if err == nil {
+					consumed <- item
+				}
+			}
+		}(i)
+	}
+
+	for i := 0; i < length; i++ {
+		list.Push(i)
+		if i%100 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	cancelStopExpiring()
+	for i := 0; i < 20; i++ {
+		<-expiringDone
+	}
+
+	// No item may be lost: if an expiring consumer swallowed a wake, the item would stay in the list
+	// while the remaining consumers keep waiting
+	seen := map[interface{}]bool{}
+	for len(seen) < length {
+		select {
+		case item := <-consumed:
+			require.False(t, seen[item], "consumed twice: %v", item)
+			seen[item] = true
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "items were not consumed", "%d of %d consumed, %d left in the list", len(seen), length, list.Length())
+		}
+	}
+	require.Equal(t, 0, list.Length())
+}
+
+func TestGetNextExpiryDoesNotSwallowWake(t *testing.T) {
+	list := NewConcurrentList()
+
+	expiringCtx, cancelExpiring := context.WithCancel(context.Background())
+	expired := make(chan error)
+	go func() {
+		_, err := list.GetNext(expiringCtx)
+		expired <- err
+	}()
+	for _, waiting := list.debug(); waiting != 1; _, waiting = list.debug() {
+		time.Sleep(time.Millisecond)
+	}
+	received := make(chan interface{})
+	go func() {
+		item, err := list.GetNext(context.Background())
+		require.NoError(t, err)
+		received <- item
+	}()
+	for _, waiting := list.debug(); waiting != 2; _, waiting = list.debug() {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The context of the first waiter expires just before it is woken up for a pushed item
+	list.lock.Lock()
+	cancelExpiring()
+	time.Sleep(10 * time.Millisecond)
+	list.data = append(list.data, list.newListItem("item", time.Now()))
+	list.dataChanged()
+	list.wakeWaiters(1)
+	list.lock.Unlock()
+
+	require.Equal(t, ErrEmptyList, <-expired)
+	select {
+	case item := <-received:
+		require.Equal(t, "item", item)
+	case <-time.After(time.Second):
+		require.FailNow(t, "the wake up of the expired waiter was not passed on")
+	}
+}
+
+func BenchmarkGetNextExpiringWaiter(b *testing.B) {
+	list := NewConcurrentList()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	totalWaiters := 10000
+	for i := 0; i < totalWaiters; i++ {
+		go func() {
+			_, _ = list.GetNext(ctx)
+		}()
+	}
+	for {
+		if _, registered := list.debug(); registered == int64(totalWaiters) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	wakeUpsBefore := atomic.LoadInt64(list.wakeUps)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		expiringCtx, cancelExpiring := context.WithTimeout(ctx, time.Microsecond)
+		_, _ = list.GetNext(expiringCtx)
+		cancelExpiring()
+	}
+	b.StopTimer()
+
+	// None of the other waiters may have been woken up by the expiring ones
+	b.ReportMetric(float64(atomic.LoadInt64(list.wakeUps)-wakeUpsBefore)/float64(b.N), "wakeups/op")
+}
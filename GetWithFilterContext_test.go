@@ -0,0 +1,40 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWithFilterContext(t *testing.T) {
+	length := 50
+	list := NewConcurrentList()
+	for i := 0; i < length; i++ {
+		list.Push(i)
+	}
+
+	items, err := list.GetWithFilterContext(context.Background(), func(item interface{}) bool {
+		return item.(int)%2 == 0
+	})
+	require.NoError(t, err)
+	require.Len(t, items, length/2)
+
+	// Cancel in the middle of the scan
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	checked := 0
+	items, err = list.GetWithFilterContext(ctx, func(item interface{}) bool {
+		checked++
+		if checked == 5 {
+			cancel()
+		}
+		return item.(int)%2 == 0
+	})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, []interface{}{0, 2, 4}, items)
+	require.Equal(t, 5, checked)
+
+	// The list is unchanged
+	require.Equal(t, length, list.Length())
+}
@@ -0,0 +1,43 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertAt(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push(1, 3)
+
+	require.NoError(t, list.InsertAt(1, 2))
+	require.NoError(t, list.InsertAt(0, 0))
+	require.NoError(t, list.InsertAt(4, 4))
+	require.Equal(t, []interface{}{0, 1, 2, 3, 4}, list.GetWithFilter(func(item interface{}) bool { return true }))
+
+	require.Equal(t, ErrIndexOutOfRange, list.InsertAt(-1, 5))
+	require.Equal(t, ErrIndexOutOfRange, list.InsertAt(6, 5))
+	require.Equal(t, 5, list.Length())
+
+	sorted := NewConcurrentList(WithSorting(func(i, j interface{}) bool {
+		return i.(int) < j.(int)
+	}))
+	require.Equal(t, ErrSortingEnabled, sorted.InsertAt(0, 1))
+	require.Equal(t, 0, sorted.Length())
+}
+
+func TestRemoveAt(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push(0, 1, 2, 3)
+
+	item, err := list.RemoveAt(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, item)
+	require.Equal(t, []interface{}{0, 2, 3}, list.GetWithFilter(func(item interface{}) bool { return true }))
+
+	_, err = list.RemoveAt(-1)
+	require.Equal(t, ErrIndexOutOfRange, err)
+	_, err = list.RemoveAt(3)
+	require.Equal(t, ErrIndexOutOfRange, err)
+	require.Equal(t, 3, list.Length())
+}
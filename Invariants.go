@@ -0,0 +1,92 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync/atomic"
+)
+
+// checkInvariants validates the internal state of the list (for testing). It checks that
+// - the items are sorted if WithSorting is used
+// - the length counter matches the number of items
+// - all items which still need to be reconstructed are accounted for (see WithLazyPersistenceLoad)
+// - there is a file for every item if WithPersistence is used
+// and returns a descriptive error for the first violation
+func (l *ConcurrentList) checkInvariants() error {
+	if l.opts.persistChanges {
+		// Pending operations would make the number of files differ
+		if err := l.FlushPersistence(); err != nil {
+			return err
+		}
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if length := atomic.LoadInt64(l.length); length != int64(len(l.data)) {
+		return fmt.Errorf("length counter is %d, but the list holds %d items", length, len(l.data))
+	}
+
+	lazyPending := 0
+	for _, item := range l.data {
+		if item.lazyPath != "" {
+			lazyPending++
+		}
+	}
+	if lazyPending != l.lazyPending {
+		return fmt.Errorf("%d items need to be reconstructed, but %d are accounted for", lazyPending, l.lazyPending)
+	}
+
+	// Pushed items may still wait for being sorted (see WithDeferredSort)
+	if l.opts.lessFunc != nil && !l.sortPending {
+		for i := 1; i < len(l.data); i++ {
+			if l.less(l.data[i], l.data[i-1]) {
+				return fmt.Errorf("item %d (%v) is sorted behind item %d (%v)", i, l.data[i].value, i-1, l.data[i-1].value)
+			}
+		}
+	}
+
+	if l.persistFiles() && l.persistBreaker == nil {
+		files, err := countFiles(l.opts.persistRootPath, l.opts.persistShardFunc != nil)
+		if err != nil {
+			return err
+		}
+		expected := len(l.data)
+		if l.persistRefs != nil {
+			// Identical items share a single file (see WithContentHashPersistence)
+			expected = len(l.persistRefs)
+		}
+		if files != expected {
+			return fmt.Errorf("%d files are persisted, but %d are expected for %d items", files, expected, len(l.data))
+		}
+	}
+
+	if l.wokenWaiters < 0 {
+		return fmt.Errorf("%d waiters were woken up", l.wokenWaiters)
+	}
+
+	return nil
+}
+
+// internal helper function for counting the files in a persistence directory (including all shards)
+func countFiles(dir string, sharded bool) (int, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, file := range files {
+		if sharded && file.IsDir() {
+			shardCount, err := countFiles(filepath.Join(dir, file.Name()), false)
+			if err != nil {
+				return 0, err
+			}
+			count += shardCount
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
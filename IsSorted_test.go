@@ -0,0 +1,32 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSorted(t *testing.T) {
+	type test struct {
+		priority int
+	}
+
+	list := NewConcurrentList(WithSorting(func(i, j interface{}) bool {
+		return i.(*test).priority < j.(*test).priority
+	}))
+	first, second := &test{priority: 1}, &test{priority: 2}
+	list.Push(second, first)
+	require.True(t, list.IsSorted())
+	require.True(t, list.VerifySorted())
+
+	// Modifying items in place corrupts the order
+	first.priority = 3
+	require.True(t, list.IsSorted())
+	require.False(t, list.VerifySorted())
+
+	// Without sorting the list is always in order
+	list.SetLessFunc(nil)
+	require.False(t, list.IsSorted())
+	require.True(t, list.VerifySorted())
+	require.False(t, NewConcurrentList().IsSorted())
+}
@@ -0,0 +1,65 @@
+package concurrentList
+
+// All returns an iterator over a snapshot of the items of the list (in the order of the list), which is taken when All is called.
+// The iterator calls yield for every item until yield returns false, e.g.
+//
+//	list.All()(func(item interface{}) bool {
+//		fmt.Println(item)
+//		return true
+//	})
+//
+// Changes to the list after All was called are not reflected and yield may use the list itself
+func (l *ConcurrentList) All() func(yield func(item interface{}) bool) {
+	data := l.snapshot()
+	return func(yield func(item interface{}) bool) {
+		for _, item := range data {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// All2 works like All, but additionally passes the index of every item within the snapshot to yield (like slices.All)
+func (l *ConcurrentList) All2() func(yield func(index int, item interface{}) bool) {
+	data := l.snapshot()
+	return func(yield func(index int, item interface{}) bool) {
+		for index, item := range data {
+			if !yield(index, item) {
+				return
+			}
+		}
+	}
+}
+
+// Snapshot returns a copy of the items of the list in the order they would be consumed
+func (l *ConcurrentList) Snapshot() []interface{} {
+	return l.snapshot()
+}
+
+// ReverseSnapshot works like Snapshot, but returns the items in reverse order (e.g. newest first for a list without WithSorting)
+func (l *ConcurrentList) ReverseSnapshot() []interface{} {
+	data := l.snapshot()
+	for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+		data[i], data[j] = data[j], data[i]
+	}
+	return data
+}
+
+// internal helper function for copying the current items of the list
+func (l *ConcurrentList) snapshot() []interface{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.values()
+}
+
+// internal helper function for copying the current items of the list. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) values() []interface{} {
+	l.loadAll()
+
+	data := make([]interface{}, len(l.data))
+	for i, item := range l.data {
+		data[i] = item.value
+	}
+	return data
+}
@@ -0,0 +1,108 @@
+package concurrentList
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// ExportJSONL writes all items of the list (in the order of the list) to w, one JSON object per line.
+// A snapshot of the list is taken first, so the list is not locked while writing. Independent of WithPersistence
+func (l *ConcurrentList) ExportJSONL(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	for _, item := range l.snapshot() {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportJSONL appends the items which were written by ExportJSONL to the list (sorting them if WithSorting is used).
+// Every line is reconstructed as an item of the same type as itemType (like WithPersistence, nil for generic JSON values).
+// Nothing is appended if any of the lines cannot be reconstructed
+func (l *ConcurrentList) ImportJSONL(r io.Reader, itemType interface{}) error {
+	// Without an itemType items are reconstructed like json.Unmarshal into an interface{} does
+	typ := reflect.TypeOf(&itemType).Elem()
+	if itemType != nil {
+		typ = reflect.TypeOf(itemType)
+	}
+
+	decoder := json.NewDecoder(r)
+	items := []interface{}{}
+	for line := 1; ; line++ {
+		tmp := reflect.New(typ)
+		err := decoder.Decode(tmp.Interface())
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("could not import line %d: %w", line, err)
+		}
+		items = append(items, tmp.Elem().Interface())
+	}
+
+	l.Push(items...)
+	return nil
+}
+
+// StreamPersisted writes the contents of all files of WithPersistence to w, one JSON object per line (like ExportJSONL).
+// The files are read one after another, the items are not reconstructed and nothing is loaded into the list (e.g. for
+// backing up a large list). Files of items implementing encoding.BinaryMarshaler cannot be streamed (they are not JSON).
+// With WithVersioning or WithSorting the records are streamed as they are (including their version and sequence).
+// The list is not locked while streaming: files which are written or deleted in the meantime may be missing.
+// Returns ErrPersistenceDisabled without persistence (or if only WithPersistenceBackend is used)
+func (l *ConcurrentList) StreamPersisted(w io.Writer) error {
+	if !l.persistFiles() {
+		return ErrPersistenceDisabled
+	}
+	return streamPersistedDir(w, l.opts.persistRootPath, l.opts.persistShardFunc != nil, &bytes.Buffer{})
+}
+
+// internal helper function for streaming all files of a directory (and its shards if sharded) as JSON lines
+// buffer is reused for every file
+func streamPersistedDir(w io.Writer, dir string, sharded bool, buffer *bytes.Buffer) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		itemPath := filepath.Join(dir, file.Name())
+
+		// Every shard is a subdirectory of the rootPath (see WithShardedPersistence)
+		if sharded && file.IsDir() {
+			err = streamPersistedDir(w, itemPath, false, buffer)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		marshaled, err := ioutil.ReadFile(itemPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// Every item must end up on a single line
+		buffer.Reset()
+		err = json.Compact(buffer, marshaled)
+		if err != nil {
+			return fmt.Errorf("could not stream %s: %w", itemPath, err)
+		}
+		buffer.WriteByte('\n')
+		_, err = w.Write(buffer.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package concurrentList
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type jsonlTest struct {
+	Name  string
+	Count int
+}
+
+func TestJSONL(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push(jsonlTest{Name: "a", Count: 1}, jsonlTest{Name: "b", Count: 2}, jsonlTest{Name: "c", Count: 3})
+
+	buffer := bytes.Buffer{}
+	require.NoError(t, list.ExportJSONL(&buffer))
+	require.Equal(t, 3, strings.Count(buffer.String(), "\n"))
+
+	imported := NewConcurrentList()
+	require.NoError(t, imported.ImportJSONL(&buffer, jsonlTest{}))
+	require.Equal(t, []interface{}{jsonlTest{Name: "a", Count: 1}, jsonlTest{Name: "b", Count: 2}, jsonlTest{Name: "c", Count: 3}}, imported.GetWithFilter(func(item interface{}) bool { return true }))
+
+	// Imported items are sorted
+	sorted := NewConcurrentList(WithSorting(func(i, j interface{}) bool { return i.(jsonlTest).Count > j.(jsonlTest).Count }))
+	require.NoError(t, list.ExportJSONL(&buffer))
+	require.NoError(t, sorted.ImportJSONL(&buffer, jsonlTest{}))
+	item, err := sorted.Shift()
+	require.NoError(t, err)
+	require.Equal(t, jsonlTest{Name: "c", Count: 3}, item)
+
+	// Generic JSON values without an itemType
+	generic := NewConcurrentList()
+	require.NoError(t, generic.ImportJSONL(strings.NewReader("1\n\"a\"\n"), nil))
+	require.Equal(t, []interface{}{float64(1), "a"}, generic.GetWithFilter(func(item interface{}) bool { return true }))
+
+	// Nothing is imported if a line is invalid
+	invalid := NewConcurrentList()
+	require.Error(t, invalid.ImportJSONL(strings.NewReader("{\"Name\":\"a\"}\n{invalid\n"), jsonlTest{}))
+	require.Equal(t, 0, invalid.Length())
+}
+
+func TestStreamPersisted(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestStreamPersisted")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithShardedPersistence(tempDir, jsonlTest{}, func(item interface{}) string {
+		return item.(jsonlTest).Name[:1]
+	}, func(item interface{}) string {
+		return item.(jsonlTest).Name
+	}))
+	items := []interface{}{jsonlTest{Name: "a1", Count: 1}, jsonlTest{Name: "a2", Count: 2}, jsonlTest{Name: "b1", Count: 3}}
+	list.Push(items...)
+
+	// Streamed from the files of all shards
+	buffer := bytes.Buffer{}
+	require.NoError(t, list.StreamPersisted(&buffer))
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	for _, item := range items {
+		marshaled, err := json.Marshal(item)
+		require.NoError(t, err)
+		require.Contains(t, lines, string(marshaled))
+	}
+
+	// The stream can be imported
+	imported := NewConcurrentList()
+	require.NoError(t, imported.ImportJSONL(&buffer, jsonlTest{}))
+	require.ElementsMatch(t, items, imported.GetWithFilter(func(item interface{}) bool { return true }))
+
+	require.Equal(t, ErrPersistenceDisabled, NewConcurrentList().StreamPersisted(&buffer))
+}
@@ -0,0 +1,56 @@
+package concurrentList
+
+import "sync/atomic"
+
+// internal helper function for reconstructing the first item of the list (see WithLazyPersistenceLoad)
+// Items which cannot be reconstructed are dropped. Pushed items which were not sorted yet are sorted first (see WithDeferredSort),
+// so the first item is correct. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) loadHead() {
+	l.applyDeferredSort()
+	for l.lazyPending > 0 && len(l.data) > 0 && l.data[0].lazyPath != "" {
+		if l.loadItem(l.data[0]) {
+			return
+		}
+		l.removeIndex(0)
+		l.dataChanged()
+	}
+}
+
+// internal helper function for reconstructing all items of the list (see WithLazyPersistenceLoad)
+// Items which cannot be reconstructed are dropped. Pushed items which were not sorted yet are sorted first (see WithDeferredSort),
+// so the order of all items is correct. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) loadAll() {
+	l.applyDeferredSort()
+	if l.lazyPending == 0 {
+		return
+	}
+
+	loaded := make([]*listItem, 0, len(l.data))
+	for _, item := range l.data {
+		if item.lazyPath == "" || l.loadItem(item) {
+			loaded = append(loaded, item)
+		}
+	}
+	atomic.AddInt64(l.totalConsumed, int64(len(l.data)-len(loaded)))
+	l.data = loaded
+	l.dataChanged()
+}
+
+// internal helper function for reconstructing a single item from its file. Returns false if that is not possible
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) loadItem(item *listItem) bool {
+	l.lazyPending--
+	value, _, err := l.persistenceReadFile(item.lazyPath)
+	if err != nil {
+		l.handleError(err)
+		if l.persistRefs != nil {
+			l.persistRefs[item.lazyPath]--
+		}
+		item.lazyPath = ""
+		return false
+	}
+
+	item.value = value
+	item.lazyPath = ""
+	return true
+}
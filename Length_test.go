@@ -0,0 +1,61 @@
+package concurrentList
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLength(t *testing.T) {
+	list := NewConcurrentList()
+	totalItems := 10000
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	// Producer
+	go func() {
+		defer wg.Done()
+		for i := 0; i < totalItems; i++ {
+			list.Push(i)
+		}
+	}()
+
+	// Consumer, gives up eventually instead of hanging the test
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go func() {
+		defer wg.Done()
+		for shifted := 0; shifted < totalItems; shifted++ {
+			if _, err := list.GetNext(ctx); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Monitor
+	done := make(chan bool)
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(100 * time.Microsecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			require.NoError(t, ctx.Err())
+			require.Equal(t, 0, list.Length())
+			return
+		case <-ticker.C:
+		}
+
+		length := list.Length()
+		require.GreaterOrEqual(t, length, 0)
+		require.LessOrEqual(t, length, totalItems)
+	}
+}
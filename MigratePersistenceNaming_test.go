@@ -0,0 +1,55 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigratePersistenceNaming(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestMigratePersistenceNaming")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}), WithAsyncPersistence(10))
+	list.Push(1, 2, 3)
+
+	require.NoError(t, list.MigratePersistenceNaming(func(item interface{}) string {
+		return fmt.Sprintf("item-%v.json", item)
+	}))
+	names := func() []string {
+		files, err := ioutil.ReadDir(tempDir)
+		require.NoError(t, err)
+		names := []string{}
+		for _, file := range files {
+			names = append(names, file.Name())
+		}
+		sort.Strings(names)
+		return names
+	}
+	require.Equal(t, []string{"item-1.json", "item-2.json", "item-3.json"}, names())
+
+	// The new naming is used from now on
+	_, err := list.Shift()
+	require.NoError(t, err)
+	list.Push(4)
+	require.NoError(t, list.FlushPersistence())
+	require.Equal(t, []string{"item-2.json", "item-3.json", "item-4.json"}, names())
+	require.NoError(t, list.checkInvariants())
+
+	// The items are intact
+	list = NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprintf("item-%v.json", item)
+	}))
+	require.Equal(t, []interface{}{2, 3, 4}, list.snapshot())
+
+	require.Equal(t, ErrPersistenceDisabled, NewConcurrentList().MigratePersistenceNaming(func(item interface{}) string { return "" }))
+}
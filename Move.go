@@ -0,0 +1,56 @@
+package concurrentList
+
+import "time"
+
+// MoveWithFilter removes all items which match a predicate from the list and appends them to dest (e.g. for moving failed items
+// to a dead-letter list) at once: no other call sees the items in neither or both lists. The files of both lists are updated.
+// Returns how many items were moved. Moving items within the same list does nothing.
+// ATTENTION: Like ReplaceAll, the items are not limited by WithCapacity, WithUniqueness or WithMaxPersistedBytes of dest
+func (l *ConcurrentList) MoveWithFilter(dest *ConcurrentList, predicate func(item interface{}) bool) int {
+	if l == dest {
+		return 0
+	}
+
+	unlock := lockBoth(l, dest)
+	moved := l.deleteWithFilter(func(item *listItem) bool {
+		return predicate(item.value)
+	})
+	added := dest.cloneAll(moved)
+	dest.appendItems(added)
+	unlock()
+
+	// Call hooks without holding the lock, so they can use the list themselves
+	if dest.opts.onPush != nil {
+		for _, item := range added {
+			(*dest.opts.onPush)(item)
+		}
+	}
+	return len(moved)
+}
+
+// internal helper function for appending items without waiting for room. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) appendItems(items []interface{}) {
+	if len(items) == 0 {
+		return
+	}
+
+	previousLength := len(l.data)
+	pushedAt := time.Now()
+	listedItems := make([]*listItem, 0, len(items))
+	for _, item := range items {
+		listedItems = append(listedItems, l.newListItem(item, pushedAt))
+	}
+	l.data = append(l.data, listedItems...)
+	l.dataChanged()
+	l.countPushed(int64(len(items)))
+	l.sortData()
+
+	if l.opts.persistChanges {
+		for _, item := range listedItems {
+			l.persistCreate(item)
+		}
+	}
+
+	l.signalNonEmpty(previousLength)
+	l.wakeForItems(len(items))
+}
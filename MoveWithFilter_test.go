@@ -0,0 +1,65 @@
+package concurrentList
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveWithFilter(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestMoveWithFilter")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	fileName := func(item interface{}) string { return item.(string) }
+	main := NewConcurrentList(WithPersistence(filepath.Join(tempDir, "main"), "", fileName))
+	dlq := NewConcurrentList(WithPersistence(filepath.Join(tempDir, "dlq"), "", fileName))
+
+	main.Push("ok-1", "failed-1", "ok-2", "failed-2")
+	dlq.Push("failed-0")
+
+	moved := main.MoveWithFilter(dlq, func(item interface{}) bool { return strings.HasPrefix(item.(string), "failed") })
+	require.Equal(t, 2, moved)
+	require.Equal(t, []interface{}{"ok-1", "ok-2"}, main.GetWithFilter(func(item interface{}) bool { return true }))
+	require.Equal(t, []interface{}{"failed-0", "failed-1", "failed-2"}, dlq.GetWithFilter(func(item interface{}) bool { return true }))
+
+	for dir, expected := range map[string]int{"main": 2, "dlq": 3} {
+		files, err := ioutil.ReadDir(filepath.Join(tempDir, dir))
+		require.NoError(t, err)
+		require.Len(t, files, expected, dir)
+	}
+	require.NoError(t, main.checkInvariants())
+	require.NoError(t, dlq.checkInvariants())
+
+	require.Equal(t, 0, main.MoveWithFilter(dlq, func(item interface{}) bool { return false }))
+	require.Equal(t, 0, main.MoveWithFilter(main, func(item interface{}) bool { return true }))
+	require.Equal(t, 2, main.Length())
+}
+
+func TestMoveWithFilterWakesConsumers(t *testing.T) {
+	source := NewConcurrentList()
+	dest := NewConcurrentList()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		source.Push(1, 2)
+		// Moving in both directions at once does not deadlock
+		for i := 0; i < 100; i++ {
+			go source.MoveWithFilter(dest, func(item interface{}) bool { return false })
+			go dest.MoveWithFilter(source, func(item interface{}) bool { return false })
+		}
+		source.MoveWithFilter(dest, func(item interface{}) bool { return item == 2 })
+	}()
+
+	item, err := dest.GetNext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, item)
+	require.Equal(t, []interface{}{1}, source.GetWithFilter(func(item interface{}) bool { return true }))
+}
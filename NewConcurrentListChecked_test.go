@@ -0,0 +1,57 @@
+package concurrentList
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConcurrentListChecked(t *testing.T) {
+	type test struct {
+		Time time.Time
+		Data string
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "TestNewConcurrentListChecked")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	fileNameFunc := func(item interface{}) string {
+		return item.(test).Time.Format(time.RFC3339Nano)
+	}
+
+	// A regular file cannot be read as a directory
+	unreadableDir := filepath.Join(tempDir, "unreadable")
+	require.NoError(t, ioutil.WriteFile(unreadableDir, []byte{}, 0644))
+
+	list, err := NewConcurrentListChecked(WithPersistence(unreadableDir, test{}, fileNameFunc))
+	require.Error(t, err)
+	require.Nil(t, list)
+
+	// Neither can a directory be created within a regular file
+	list, err = NewConcurrentListChecked(WithPersistence(filepath.Join(unreadableDir, "sub"), test{}, fileNameFunc))
+	require.Error(t, err)
+	require.Nil(t, list)
+
+	// A missing directory is created
+	missingDir := filepath.Join(tempDir, "missing")
+	list, err = NewConcurrentListChecked(WithPersistence(missingDir, test{}, fileNameFunc))
+	require.NoError(t, err)
+	require.NotNil(t, list)
+	_, err = os.Stat(missingDir)
+	require.NoError(t, err)
+
+	// The infallible constructor passes the error to the errorHandler
+	var handledErr error
+	list = NewConcurrentList(WithPersistence(unreadableDir, test{}, fileNameFunc, func(err error) {
+		handledErr = err
+	}))
+	require.NotNil(t, list)
+	require.Error(t, handledErr)
+}
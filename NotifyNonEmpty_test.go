@@ -0,0 +1,58 @@
+package concurrentList
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyNonEmpty(t *testing.T) {
+	list := NewConcurrentList()
+	notify := list.NotifyNonEmpty()
+
+	requireSignals := func(expected int) {
+		received := 0
+		for {
+			select {
+			case <-notify:
+				received++
+				continue
+			case <-time.After(10 * time.Millisecond):
+			}
+			break
+		}
+		require.Equal(t, expected, received)
+	}
+
+	requireSignals(0)
+
+	// empty -> non-empty
+	list.Push(1)
+	requireSignals(1)
+
+	// non-empty -> non-empty
+	list.Push(2)
+	list.Push(3)
+	requireSignals(0)
+
+	// non-empty -> empty
+	for i := 0; i < 3; i++ {
+		_, err := list.Shift()
+		require.NoError(t, err)
+	}
+	requireSignals(0)
+
+	// empty -> non-empty
+	list.Push(4)
+	requireSignals(1)
+
+	// multiple transitions are coalesced if not received
+	_, err := list.Shift()
+	require.NoError(t, err)
+	list.Push(5)
+	_, err = list.Shift()
+	require.NoError(t, err)
+	list.Push(6)
+	requireSignals(1)
+}
@@ -0,0 +1,30 @@
+package concurrentList
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOldestAge(t *testing.T) {
+	list := NewConcurrentList()
+
+	_, err := list.OldestAge()
+	require.Equal(t, ErrEmptyList, err)
+
+	list.Push(1)
+	time.Sleep(50 * time.Millisecond)
+	list.Push(2)
+
+	age, err := list.OldestAge()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, int64(age), int64(50*time.Millisecond))
+
+	_, err = list.Shift()
+	require.NoError(t, err)
+
+	age, err = list.OldestAge()
+	require.NoError(t, err)
+	require.Less(t, int64(age), int64(50*time.Millisecond))
+}
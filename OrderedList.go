@@ -0,0 +1,43 @@
+package concurrentList
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NewOrderedList creates a ConcurrentList which is sorted by the natural order of its items (see WithSorting) without a lessFunc:
+// the smallest item is returned first. Items must all be of the same kind of ordered type (integers, floats or strings,
+// including named types like time.Duration), NaN is smaller than all other floats. Other items cause a panic when they are sorted
+func NewOrderedList(opts ...ConcurrentListOption) *ConcurrentList {
+	return NewConcurrentList(append([]ConcurrentListOption{WithSorting(orderedLess)}, opts...)...)
+}
+
+// NewOrderedListDescending works like NewOrderedList, but returns the greatest item first
+func NewOrderedListDescending(opts ...ConcurrentListOption) *ConcurrentList {
+	return NewConcurrentList(append([]ConcurrentListOption{WithSorting(func(i, j interface{}) bool {
+		return orderedLess(j, i)
+	})}, opts...)...)
+}
+
+// orderedLess reports if i is less than j according to their natural order (like cmp.Less)
+func orderedLess(i, j interface{}) bool {
+	a, b := reflect.ValueOf(i), reflect.ValueOf(j)
+	if a.Kind() != b.Kind() {
+		panic(fmt.Sprintf("concurrentList: cannot order %T and %T", i, j))
+	}
+
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		x, y := a.Float(), b.Float()
+		// NaN is smaller than any other value (but not than NaN)
+		return (x != x && y == y) || x < y
+	case reflect.String:
+		return a.String() < b.String()
+	default:
+		panic(fmt.Sprintf("concurrentList: cannot order %T", i))
+	}
+}
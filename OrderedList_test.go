@@ -0,0 +1,38 @@
+package concurrentList
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOrderedList(t *testing.T) {
+	list := NewOrderedList()
+	list.Push(3, 1, 4, 1, 5, 9, 2, 6)
+	for _, expected := range []int{1, 1, 2, 3, 4, 5, 6, 9} {
+		item, err := list.GetNext(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, expected, item)
+	}
+
+	strings := NewOrderedListDescending()
+	strings.Push("b", "c", "a")
+	require.Equal(t, []interface{}{"c", "b", "a"}, strings.GetWithFilter(func(item interface{}) bool { return true }))
+
+	// Named types and NaN
+	durations := NewOrderedList()
+	durations.Push(time.Second, time.Millisecond, time.Minute)
+	require.Equal(t, []interface{}{time.Millisecond, time.Second, time.Minute}, durations.GetWithFilter(func(item interface{}) bool { return true }))
+
+	floats := NewOrderedList()
+	floats.Push(1.5, math.NaN(), -1.0)
+	items := floats.GetWithFilter(func(item interface{}) bool { return true })
+	require.True(t, math.IsNaN(items[0].(float64)))
+	require.Equal(t, []interface{}{-1.0, 1.5}, items[1:])
+
+	require.Panics(t, func() { orderedLess(struct{}{}, struct{}{}) })
+	require.Panics(t, func() { orderedLess(1, "a") })
+}
@@ -0,0 +1,41 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartition(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestPartition")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	original := []interface{}{1, 2, 3, 4, 5, 6}
+	list.Push(original...)
+
+	removed, remaining := list.Partition(func(item interface{}) bool { return item.(int)%3 == 0 })
+	require.Equal(t, []interface{}{3, 6}, removed)
+	require.Equal(t, []interface{}{1, 2, 4, 5}, remaining)
+
+	// Disjoint and together the original contents
+	require.ElementsMatch(t, original, append(append([]interface{}{}, removed...), remaining...))
+	require.Equal(t, remaining, list.GetWithFilter(func(item interface{}) bool { return true }))
+
+	// The files of the removed items are deleted
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+
+	removed, remaining = list.Partition(func(item interface{}) bool { return false })
+	require.Empty(t, removed)
+	require.Len(t, remaining, 4)
+}
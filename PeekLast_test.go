@@ -0,0 +1,28 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeekLast(t *testing.T) {
+	list := NewConcurrentList()
+	_, err := list.PeekLast()
+	require.Equal(t, ErrEmptyList, err)
+
+	list.Push(2, 3, 1)
+	last, err := list.PeekLast()
+	require.NoError(t, err)
+	require.Equal(t, 1, last)
+	require.Equal(t, 3, list.Length())
+
+	sorted := NewConcurrentList(WithSorting(func(i, j interface{}) bool {
+		return i.(int) < j.(int)
+	}))
+	sorted.Push(2, 3, 1)
+	last, err = sorted.PeekLast()
+	require.NoError(t, err)
+	require.Equal(t, 3, last)
+	require.Equal(t, 3, sorted.Length())
+}
@@ -0,0 +1,30 @@
+package concurrentList
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeekMatching(t *testing.T) {
+	list := NewMaxPriorityList(func(item interface{}) int {
+		return item.(int)
+	}, WithDeferredSort(time.Hour))
+	list.Push(3, 8, 5, 10, 7)
+
+	// Reflects the order of the list even if sorting was deferred
+	odd := func(item interface{}) bool { return item.(int)%2 == 1 }
+	item, ok := list.PeekMatching(odd)
+	require.True(t, ok)
+	require.Equal(t, 7, item)
+	require.Equal(t, 5, list.Length())
+
+	list.Push(9)
+	item, ok = list.PeekMatching(odd)
+	require.True(t, ok)
+	require.Equal(t, 9, item)
+
+	_, ok = list.PeekMatching(func(item interface{}) bool { return item.(int) > 10 })
+	require.False(t, ok)
+}
@@ -0,0 +1,33 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeekWhere(t *testing.T) {
+	list := NewConcurrentList()
+	_, ok := list.PeekWhere(func(item interface{}) bool { return true })
+	require.False(t, ok)
+
+	list.Push(1, 2, 3, 4)
+
+	// Match at the head
+	item, ok := list.PeekWhere(func(item interface{}) bool { return item.(int) < 3 })
+	require.True(t, ok)
+	require.Equal(t, 1, item)
+
+	// Match in the middle: the first one wins
+	item, ok = list.PeekWhere(func(item interface{}) bool { return item.(int)%2 == 0 })
+	require.True(t, ok)
+	require.Equal(t, 2, item)
+
+	// No match
+	item, ok = list.PeekWhere(func(item interface{}) bool { return item.(int) > 4 })
+	require.False(t, ok)
+	require.Nil(t, item)
+
+	// Nothing is removed
+	require.Equal(t, 4, list.Length())
+}
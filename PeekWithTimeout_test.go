@@ -0,0 +1,50 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeekWithTimeout(t *testing.T) {
+	list := NewConcurrentList()
+
+	// Nothing arrives in time
+	started := time.Now()
+	_, err := list.PeekWithTimeout(20 * time.Millisecond)
+	require.Equal(t, ErrEmptyList, err)
+	require.True(t, time.Since(started) >= 20*time.Millisecond)
+
+	// An item arrives before the timeout and is not consumed
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		list.Push(1)
+	}()
+	item, err := list.PeekWithTimeout(time.Second)
+	require.NoError(t, err)
+	require.Equal(t, 1, item)
+	require.Equal(t, 1, list.Length())
+
+	// Returns right away if there is an item
+	item, err = list.PeekWithTimeout(0)
+	require.NoError(t, err)
+	require.Equal(t, 1, item)
+}
+
+func TestPeekWithContext(t *testing.T) {
+	list := NewConcurrentList()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := list.PeekWithContext(ctx)
+	require.Equal(t, ErrEmptyList, err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, list.Close())
+	}()
+	_, err = list.PeekWithContext(context.Background())
+	require.Equal(t, ErrClosed, err)
+}
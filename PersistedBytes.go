@@ -0,0 +1,78 @@
+package concurrentList
+
+// internal helper function for determining the sizes of the files of items (see WithMaxPersistedBytes)
+// returns nil if the size of the files is not limited. Items which cannot be marshaled have a size of 0
+func (l *ConcurrentList) persistedSizes(items []interface{}) []int64 {
+	if !l.opts.persistChanges || l.opts.maxPersistedBytes <= 0 {
+		return nil
+	}
+
+	sizes := make([]int64, len(items))
+	for i, item := range items {
+		marshaled, err := persistenceMarshal(item, l.opts.persistJSONEncoder)
+		if err == nil {
+			sizes[i] = int64(len(marshaled))
+		}
+	}
+	return sizes
+}
+
+// internal helper function for summing up the sizes of the files of all items. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) persistedBytes() int64 {
+	if !l.opts.persistChanges || l.opts.maxPersistedBytes <= 0 {
+		return 0
+	}
+
+	total := int64(0)
+	for _, item := range l.data {
+		total += item.size
+	}
+	return total
+}
+
+// internal helper function for dropping the oldest items (up to sequence oldest) until needed bytes fit in addition to
+// persistedBytes (see WithMaxPersistedBytes). Returns the remaining persistedBytes. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) evictPersistedBytes(persistedBytes int64, needed int64, oldest uint64) int64 {
+	for persistedBytes+needed > l.opts.maxPersistedBytes {
+		index := -1
+		for i, item := range l.data {
+			if item.sequence <= oldest && (index < 0 || item.sequence < l.data[index].sequence) {
+				index = i
+			}
+		}
+		if index < 0 {
+			return persistedBytes
+		}
+
+		evicted := l.data[index]
+		l.removeIndex(index)
+		l.dataChanged()
+		persistedBytes -= evicted.size
+
+		// Items which were not reconstructed yet are needed for deleting their files (see WithLazyPersistenceLoad)
+		if evicted.lazyPath != "" && !l.loadItem(evicted) {
+			continue
+		}
+		l.persistDelete(evicted.value)
+		l.discard(evicted.value)
+	}
+	return persistedBytes
+}
+
+// internal helper function for checking if all items fit into WithMaxPersistedBytes once all previous items are dropped.
+// Items whose key is in keys already are not counted (see WithUniqueness). the caller needs to make sure the collection is locked
+func (l *ConcurrentList) fitPersistedBytes(items []interface{}, sizes []int64, keys map[string]bool) bool {
+	added := map[string]bool{}
+	total := int64(0)
+	for i, item := range items {
+		if keys != nil {
+			key := (*l.opts.uniqueKeyFunc)(item)
+			if keys[key] || added[key] {
+				continue
+			}
+			added[key] = true
+		}
+		total += sizes[i]
+	}
+	return total <= l.opts.maxPersistedBytes
+}
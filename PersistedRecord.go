@@ -0,0 +1,65 @@
+package concurrentList
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+)
+
+// persistedRecord is the contents of a file of WithPersistence with WithVersioning or WithSorting: the marshaled item along
+// with its version and, for sorted lists, the sequence it was pushed with. Without either the item is written as it is
+type persistedRecord struct {
+	Version  uint64 `json:"version,omitempty"`
+	Sequence uint64 `json:"sequence,omitempty"`
+
+	// The item as json or, if it implements encoding.BinaryMarshaler, as binary
+	Item   json.RawMessage `json:"item,omitempty"`
+	Binary []byte          `json:"binary,omitempty"`
+}
+
+// internal helper function for wrapping the marshaled item into a record with the next version of its file (see WithVersioning)
+// and its sequence (0 if the list is not sorted). Returns marshaled as it is if neither needs to be persisted
+func (l *ConcurrentList) persistenceWrapRecord(item interface{}, itemPath string, sequence uint64, marshaled []byte) ([]byte, error) {
+	if l.persistVersions == nil && sequence == 0 {
+		return marshaled, nil
+	}
+
+	record := persistedRecord{Sequence: sequence}
+	if l.persistVersions != nil {
+		record.Version = l.persistenceNextVersion(itemPath)
+	}
+	if _, ok := item.(encoding.BinaryMarshaler); ok {
+		record.Binary = marshaled
+	} else {
+		record.Item = marshaled
+	}
+	return json.Marshal(record)
+}
+
+// internal helper function for unwrapping the marshaled item and its sequence from the contents of a file (remembering its
+// version with WithVersioning). Files which were written as they are are returned unchanged (with version and sequence 0)
+func (l *ConcurrentList) persistenceUnwrapRecord(itemPath string, contents []byte) ([]byte, uint64) {
+	record, ok := persistenceParseRecord(contents)
+	if l.persistVersions != nil {
+		l.persistenceRememberVersion(itemPath, record.Version)
+	}
+	if !ok {
+		return contents, 0
+	}
+	if record.Binary != nil {
+		return record.Binary, record.Sequence
+	}
+	return record.Item, record.Sequence
+}
+
+// the record in the contents of a file of WithPersistence. Contents are only considered a record if they consist of
+// nothing but its fields, so items which happen to have a field named like one of them are not mistaken for one
+func persistenceParseRecord(contents []byte) (persistedRecord, bool) {
+	record := persistedRecord{}
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&record); err != nil || (record.Item == nil && record.Binary == nil) {
+		return persistedRecord{}, false
+	}
+	return record, true
+}
@@ -0,0 +1,59 @@
+package concurrentList
+
+import "time"
+
+// PersistenceBackend persists the items of a list somewhere else than in the directory of WithPersistence
+// (e.g. a second directory or a remote store, see WithPersistenceBackend). Methods are called one at a time
+type PersistenceBackend interface {
+	// Store persists a pushed item
+	Store(item interface{}) error
+	// Delete removes a persisted item once it left the list
+	Delete(item interface{}) error
+	// Load returns all persisted items (in the order they were pushed) when the list is created
+	Load() ([]interface{}, error)
+}
+
+// internal helper function for checking if items are persisted in the directory of WithPersistence (in contrast to
+// WithPersistenceBackend only)
+func (l *ConcurrentList) persistFiles() bool {
+	return l.opts.persistFileNameFunc != nil
+}
+
+// internal helper function for reconstructing the list from the first backend of WithPersistenceBackend
+// (if the list is not persisted in a directory). the caller needs to make sure the collection is locked
+func (l *ConcurrentList) persistenceLoadBackend() error {
+	if len(l.opts.persistBackends) == 0 {
+		return nil
+	}
+
+	items, err := l.opts.persistBackends[0].Load()
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		l.data = append(l.data, l.newListItem(item, time.Now()))
+	}
+	l.dataChanged()
+	return nil
+}
+
+// internal helper function for storing or deleting an item in all backends of WithPersistenceBackend.
+// Returns the first error, all errors are handled
+func (l *ConcurrentList) persistenceApplyBackends(item interface{}, delete bool) error {
+	var first error
+	for _, backend := range l.opts.persistBackends {
+		var err error
+		if delete {
+			err = backend.Delete(item)
+		} else {
+			err = backend.Store(item)
+		}
+		if err != nil {
+			l.handleError(err)
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
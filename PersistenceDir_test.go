@@ -0,0 +1,28 @@
+package concurrentList
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistenceDir(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestPersistenceDir")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	dir, ok := list.PersistenceDir()
+	require.True(t, ok)
+	require.Equal(t, tempDir, dir)
+
+	dir, ok = NewConcurrentList().PersistenceDir()
+	require.False(t, ok)
+	require.Equal(t, "", dir)
+}
@@ -0,0 +1,38 @@
+package concurrentList
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistenceHealthy(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestPersistenceHealthy")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	require.True(t, list.PersistenceHealthy())
+	list.Push(1)
+	require.True(t, list.PersistenceHealthy())
+
+	// Writes fail once the directory is gone, but the list keeps working in memory
+	require.NoError(t, os.RemoveAll(tempDir))
+	list.Push(2)
+	require.False(t, list.PersistenceHealthy())
+	require.Equal(t, 2, list.Length())
+	require.NotEmpty(t, list.Errors())
+
+	// Healthy again with the next successful write
+	require.NoError(t, os.MkdirAll(tempDir, 0755))
+	list.Push(3)
+	require.True(t, list.PersistenceHealthy())
+
+	require.True(t, NewConcurrentList().PersistenceHealthy())
+}
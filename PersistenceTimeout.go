@@ -0,0 +1,67 @@
+package concurrentList
+
+import (
+	"fmt"
+	"time"
+)
+
+// internal helper function for performing a persistence operation, but waiting no longer than WithPersistenceTimeout.
+// An operation which timed out keeps running in the background. Until it is done, all further operations are skipped and
+// fail with ErrPersistenceTimeout right away, so a hanging filesystem neither blocks the list nor piles up goroutines
+func (l *ConcurrentList) persistenceApplyTimeout(op persistOperation) error {
+	if l.persistenceTimedOut() {
+		err := fmt.Errorf("%w: skipped while a previous operation is still running", ErrPersistenceTimeout)
+		l.handleError(err)
+		return err
+	}
+
+	result := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		result <- l.persistenceApplyIO(op)
+	}()
+
+	timer := time.NewTimer(l.opts.persistTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		l.persistTimeoutLock.Lock()
+		l.persistTimeoutPending = done
+		l.persistTimeoutLock.Unlock()
+
+		l.handleError(ErrPersistenceTimeout)
+		return ErrPersistenceTimeout
+	}
+}
+
+// internal helper function for checking if an operation which timed out is still running
+func (l *ConcurrentList) persistenceTimedOut() bool {
+	l.persistTimeoutLock.Lock()
+	defer l.persistTimeoutLock.Unlock()
+
+	if l.persistTimeoutPending == nil {
+		return false
+	}
+	select {
+	case <-l.persistTimeoutPending:
+		l.persistTimeoutPending = nil
+		return false
+	default:
+		return true
+	}
+}
+
+// internal helper function for waiting until the operation which exceeded WithPersistenceTimeout (if any) is done,
+// so it does not interfere with rewriting the directory (see RebuildPersistence)
+func (l *ConcurrentList) waitPersistenceTimeouts() {
+	l.persistTimeoutLock.Lock()
+	pending := l.persistTimeoutPending
+	l.persistTimeoutLock.Unlock()
+
+	if pending != nil {
+		<-pending
+	}
+}
@@ -0,0 +1,42 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPopMatching(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestPopMatching")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+
+	for _, i := range []int{1, 8, 3, 2, 5, 6, 4, 7} {
+		list.Push(i)
+	}
+
+	isEven := func(item interface{}) bool {
+		return item.(int)%2 == 0
+	}
+
+	require.Equal(t, []interface{}{8, 2, 6}, list.PopMatching(isEven, 3))
+	require.Equal(t, []interface{}{1, 3, 5, 4, 7}, list.GetWithFilter(func(item interface{}) bool { return true }))
+
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 5)
+
+	require.Equal(t, []interface{}{4}, list.PopMatching(isEven, 3))
+	require.Empty(t, list.PopMatching(isEven, 3))
+	require.Equal(t, 4, list.Length())
+}
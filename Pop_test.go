@@ -0,0 +1,111 @@
+package concurrentList
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPop(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestPop")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	_, err := list.Pop()
+	require.Equal(t, ErrEmptyList, err)
+
+	list.Push(1, 2, 3)
+	for _, expected := range []int{3, 2, 1} {
+		item, err := list.Pop()
+		require.NoError(t, err)
+		require.Equal(t, expected, item)
+	}
+
+	// The files are deleted
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+
+	// Queue and stack at the same time
+	list.Push(4, 5, 6)
+	item, err := list.Shift()
+	require.NoError(t, err)
+	require.Equal(t, 4, item)
+	item, err = list.Pop()
+	require.NoError(t, err)
+	require.Equal(t, 6, item)
+}
+
+func TestPopNext(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push(1, 2)
+
+	item, err := list.PopNext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, item)
+
+	// Blocks until an item is available
+	_, err = list.PopNext(context.Background())
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		list.Push(3)
+	}()
+	item, err = list.PopNext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, item)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = list.PopNext(ctx)
+	require.Equal(t, ErrEmptyList, err)
+}
+
+func TestPopNextDelayedProducer(t *testing.T) {
+	list := NewConcurrentList()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		list.Push(1, 2, 3)
+	}()
+
+	// The most recently pushed item is returned once the producer pushed
+	item, err := list.PopNext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, item)
+
+	// Cancellation is honored while waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	list.Drain()
+	_, err = list.PopNext(ctx)
+	require.Equal(t, ErrEmptyList, err)
+	require.NoError(t, list.checkInvariants())
+}
+
+func TestPopNextWithTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	list := NewConcurrentList(WithTracer(tracer))
+	list.Push(1)
+
+	_, err := list.PopNext(context.Background())
+	require.NoError(t, err)
+
+	tracer.lock.Lock()
+	defer tracer.lock.Unlock()
+	require.Equal(t, 1, tracer.starts)
+	require.NoError(t, tracer.errs[0])
+}
@@ -0,0 +1,57 @@
+package concurrentList
+
+// NewPriorityList creates a ConcurrentList which is sorted by the priority of its items (see WithSorting):
+// items with the lowest priority value are returned first
+func NewPriorityList(priority func(item interface{}) int, opts ...ConcurrentListOption) *ConcurrentList {
+	return NewConcurrentList(append([]ConcurrentListOption{withPriority(priority, false), WithSorting(func(i, j interface{}) bool {
+		return priority(i) < priority(j)
+	})}, opts...)...)
+}
+
+// NewMaxPriorityList creates a ConcurrentList which is sorted by the priority of its items (see WithSorting):
+// items with the highest priority value are returned first
+func NewMaxPriorityList(priority func(item interface{}) int, opts ...ConcurrentListOption) *ConcurrentList {
+	return NewConcurrentList(append([]ConcurrentListOption{withPriority(priority, true), WithSorting(func(i, j interface{}) bool {
+		return priority(i) > priority(j)
+	})}, opts...)...)
+}
+
+// PushWithPriority appends an item just like Push, but sorts it according to the passed priority instead of the priority
+// which is derived from the item (e.g. for enqueuing the same value at a different urgency)
+// Returns ErrPriorityDisabled if the list was not created by NewPriorityList or NewMaxPriorityList
+// ATTENTION: The override is not persisted, items which are reconstructed from persistence use the derived priority again
+func (l *ConcurrentList) PushWithPriority(item interface{}, priority int) error {
+	l.lock.Lock()
+	enabled := l.opts.priorityFunc != nil
+	l.lock.Unlock()
+	if !enabled {
+		return ErrPriorityDisabled
+	}
+
+	l.push([]interface{}{item}, pushOptions{priority: &priority})
+	return nil
+}
+
+// internal option for remembering how priorities are derived from items, so they can be overridden per item
+func withPriority(priority func(item interface{}) int, descending bool) ConcurrentListOption {
+	return newFuncConcurrentListOption(func(o *concurrentListOptions) {
+		o.priorityFunc = &priority
+		o.priorityDescending = descending
+	})
+}
+
+// internal helper function for comparing two items of a priority list. Overridden priorities take precedence
+func (l *ConcurrentList) lessPriority(i, j *listItem) bool {
+	if l.opts.priorityDescending {
+		return l.itemPriority(i) > l.itemPriority(j)
+	}
+	return l.itemPriority(i) < l.itemPriority(j)
+}
+
+// internal helper function for getting the priority of an item
+func (l *ConcurrentList) itemPriority(item *listItem) int {
+	if item.priority != nil {
+		return *item.priority
+	}
+	return (*l.opts.priorityFunc)(item.value)
+}
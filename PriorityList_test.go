@@ -0,0 +1,78 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityList(t *testing.T) {
+	type test struct {
+		item     string
+		priority int
+	}
+
+	priority := func(item interface{}) int {
+		return item.(test).priority
+	}
+	items := []test{
+		{item: "prio200", priority: 200},
+		{item: "prio500", priority: 500},
+		{item: "prio100", priority: 100},
+		{item: "prio300", priority: 300},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	list := NewPriorityList(priority)
+	for _, item := range items {
+		list.Push(item)
+	}
+	for _, expected := range []string{"prio100", "prio200", "prio300", "prio500"} {
+		item, err := list.GetNext(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, item.(test).item)
+	}
+
+	list = NewMaxPriorityList(priority)
+	for _, item := range items {
+		list.Push(item)
+	}
+	for _, expected := range []string{"prio500", "prio300", "prio200", "prio100"} {
+		item, err := list.GetNext(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, item.(test).item)
+	}
+}
+
+func TestPushWithPriority(t *testing.T) {
+	list := NewPriorityList(func(item interface{}) int {
+		return len(item.(string))
+	})
+
+	list.Push("derived8")
+	require.NoError(t, list.PushWithPriority("a", 20))
+	require.NoError(t, list.PushWithPriority("long but urgent", 0))
+	require.NoError(t, list.PushWithPriority("bb", 5))
+
+	// Consume order follows the overrides over the priority derived from the item (its length),
+	// which would be "a", "bb", "derived8", "long but urgent"
+	require.Equal(t, []interface{}{"long but urgent", "bb", "derived8", "a"}, list.TopK(4))
+	for _, expected := range []interface{}{"long but urgent", "bb", "derived8", "a"} {
+		item, err := list.Shift()
+		require.NoError(t, err)
+		require.Equal(t, expected, item)
+	}
+
+	maxList := NewMaxPriorityList(func(item interface{}) int {
+		return 0
+	})
+	maxList.Push("derived")
+	require.NoError(t, maxList.PushWithPriority("urgent", 10))
+	require.NoError(t, maxList.PushWithPriority("late", -10))
+	require.Equal(t, []interface{}{"urgent", "derived", "late"}, maxList.TopK(3))
+
+	require.Equal(t, ErrPriorityDisabled, NewConcurrentList().PushWithPriority("item", 1))
+}
@@ -0,0 +1,126 @@
+package concurrentList
+
+import (
+	"context"
+	"time"
+)
+
+// ProcessNext blocks until an item is available (just like GetNext) and calls handler with it, but WITHOUT removing it first.
+// The item is only removed (including its file) if handler returns nil. Otherwise it stays in the list at its position
+// so it is processed again by the next call (see WithRetryPolicy), and the error of handler is returned.
+// While an item is processed, concurrent ProcessNext calls skip it and process the next item instead (or wait for one).
+// ATTENTION: This only guards against other ProcessNext calls, all other methods (e.g. GetNext) still see the item
+func (l *ConcurrentList) ProcessNext(ctx context.Context, handler func(item interface{}) error) error {
+	if ctx.Err() != nil {
+		return ErrEmptyList
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	item, err := l.claimNext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = l.processClaimed(item, handler)
+	if err != nil {
+		l.retryLater(item, err)
+		return err
+	}
+
+	if l.removeItem(item) {
+		l.countShifted()
+	}
+	return nil
+}
+
+// internal helper function for calling handler with a claimed item without holding the lock. The lock is acquired
+// again and the claim released even if handler panics (the panic is passed on).
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) processClaimed(item *listItem, handler func(item interface{}) error) error {
+	l.lock.Unlock()
+	defer func() {
+		l.lock.Lock()
+		item.claimed = false
+		// Someone might be waiting for an item which is not claimed
+		l.notifyChange()
+	}()
+
+	return handler(item.value)
+}
+
+// internal helper function for recording a failed attempt of ProcessNext according to WithRetryPolicy.
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) retryLater(item *listItem, err error) {
+	if l.opts.retryBackoff == nil {
+		return
+	}
+
+	item.attempts++
+	if l.opts.retryMaxAttempts > 0 && item.attempts >= l.opts.retryMaxAttempts {
+		if l.removeItem(item) && l.opts.deadLetter != nil {
+			(*l.opts.deadLetter)(item.value, err)
+		}
+		return
+	}
+	item.notBefore = time.Now().Add((*l.opts.retryBackoff)(item.attempts))
+}
+
+// internal helper function for removing an item (including its file). the caller needs to make sure the collection is locked
+// returns false if the item is not part of the list (anymore)
+func (l *ConcurrentList) removeItem(item *listItem) bool {
+	for i, existing := range l.data {
+		if existing == item {
+			l.removeIndex(i)
+			l.dataChanged()
+			if l.opts.persistChanges {
+				l.persistDelete(item.value)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// internal helper function for waiting until an item is available which is neither claimed by ProcessNext nor waiting
+// for its backoff (see WithRetryPolicy) and claiming it. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) claimNext(ctx context.Context) (*listItem, error) {
+	for {
+		l.dropExpiredHead()
+		l.loadHead()
+		now := time.Now()
+		if len(l.data) > 0 && (l.data[0].claimed || l.data[0].notBefore.After(now)) {
+			l.loadAll()
+		}
+
+		retryAt := time.Time{}
+		for _, item := range l.data {
+			switch {
+			case item.claimed:
+			case item.notBefore.After(now):
+				if retryAt.IsZero() || item.notBefore.Before(retryAt) {
+					retryAt = item.notBefore
+				}
+			default:
+				item.claimed = true
+				return item, nil
+			}
+		}
+
+		if l.closed {
+			return nil, ErrClosed
+		}
+
+		// Wait for a change, but not longer than until the next backoff is over
+		waitCtx, cancel := ctx, func() {}
+		if !retryAt.IsZero() {
+			waitCtx, cancel = context.WithDeadline(ctx, retryAt)
+		}
+		_ = l.waitChange(waitCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return nil, ErrEmptyList
+		}
+	}
+}
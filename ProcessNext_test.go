@@ -0,0 +1,113 @@
+package concurrentList
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessNext(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestProcessNext")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	list.Push(1, 2)
+
+	// A failing handler keeps the item at the head (including its file)
+	failure := errors.New("failure")
+	processed := map[interface{}]int{}
+	err := list.ProcessNext(context.Background(), func(item interface{}) error {
+		require.Equal(t, 2, list.Length())
+		return failure
+	})
+	require.Equal(t, failure, err)
+	require.Equal(t, 2, list.Length())
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, list.ProcessNext(context.Background(), func(item interface{}) error {
+			processed[item]++
+			return nil
+		}))
+	}
+	require.Equal(t, map[interface{}]int{1: 1, 2: 1}, processed)
+	require.Equal(t, 0, list.Length())
+	files, err = ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 0)
+	require.NoError(t, list.checkInvariants())
+
+	// Nothing to process
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Equal(t, ErrEmptyList, list.ProcessNext(ctx, func(item interface{}) error { return nil }))
+}
+
+func TestProcessNextConcurrent(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push(1)
+
+	// The second call must not process the item which is being processed by the first one
+	started := make(chan struct{})
+	release := make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.NoError(t, list.ProcessNext(context.Background(), func(item interface{}) error {
+			require.Equal(t, 1, item)
+			close(started)
+			<-release
+			return nil
+		}))
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Equal(t, ErrEmptyList, list.ProcessNext(ctx, func(item interface{}) error { return nil }))
+
+	// It processes the next item instead
+	list.Push(2)
+	require.NoError(t, list.ProcessNext(context.Background(), func(item interface{}) error {
+		require.Equal(t, 2, item)
+		return nil
+	}))
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, 0, list.Length())
+}
+
+func TestProcessNextPanic(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push(1)
+
+	require.PanicsWithValue(t, "failure", func() {
+		_ = list.ProcessNext(context.Background(), func(item interface{}) error {
+			panic("failure")
+		})
+	})
+
+	// The item is neither removed nor claimed anymore and the list is not locked
+	require.Equal(t, 1, list.Length())
+	require.NoError(t, list.ProcessNext(context.Background(), func(item interface{}) error {
+		require.Equal(t, 1, item)
+		return nil
+	}))
+	require.Equal(t, 0, list.Length())
+}
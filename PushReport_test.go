@@ -0,0 +1,31 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushReport(t *testing.T) {
+	list := NewConcurrentList(WithSorting(func(i, j interface{}) bool {
+		return i.(int) < j.(int)
+	}))
+
+	require.False(t, list.PushReport(5))
+	require.False(t, list.PushReport(10))
+
+	// Sorts behind all existing items
+	require.False(t, list.PushReport(20, 15))
+
+	// Sorts to the front
+	require.True(t, list.PushReport(1))
+	require.Equal(t, []interface{}{1, 5, 10, 15, 20}, list.TopK(5))
+
+	// Sorts in between
+	require.True(t, list.PushReport(12))
+
+	// Without sorting items are only ever appended
+	unsorted := NewConcurrentList()
+	require.False(t, unsorted.PushReport(5))
+	require.False(t, unsorted.PushReport(1))
+}
@@ -0,0 +1,20 @@
+//go:build debug
+// +build debug
+
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushSortedUnsorted(t *testing.T) {
+	list := NewConcurrentList(WithSorting(func(i, j interface{}) bool {
+		return i.(int) < j.(int)
+	}))
+
+	require.Panics(t, func() {
+		list.PushSorted([]interface{}{1, 3, 2})
+	})
+}
@@ -0,0 +1,72 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushSorted(t *testing.T) {
+	type test struct {
+		ID       string
+		Priority int
+	}
+
+	list := NewConcurrentList(WithSorting(func(i, j interface{}) bool {
+		return i.(test).Priority < j.(test).Priority
+	}))
+	list.Push(test{ID: "a", Priority: 1}, test{ID: "b", Priority: 3}, test{ID: "c", Priority: 5})
+
+	// Equal items are merged behind the existing ones
+	list.PushSorted([]interface{}{test{ID: "d", Priority: 0}, test{ID: "e", Priority: 3}, test{ID: "f", Priority: 4}, test{ID: "g", Priority: 9}})
+	require.Equal(t, []interface{}{
+		test{ID: "d", Priority: 0},
+		test{ID: "a", Priority: 1},
+		test{ID: "b", Priority: 3},
+		test{ID: "e", Priority: 3},
+		test{ID: "f", Priority: 4},
+		test{ID: "c", Priority: 5},
+		test{ID: "g", Priority: 9},
+	}, list.TopK(7))
+	require.NoError(t, list.checkInvariants())
+
+	// Without sorting the items are appended
+	unsorted := NewConcurrentList()
+	unsorted.Push(2)
+	unsorted.PushSorted([]interface{}{1, 3})
+	require.Equal(t, []interface{}{2, 1, 3}, unsorted.TopK(3))
+}
+
+func benchmarkPushPresorted(b *testing.B, push func(list *ConcurrentList, items []interface{})) {
+	existing := make([]interface{}, 0, 100000)
+	for i := 0; i < 100000; i++ {
+		existing = append(existing, 2*i)
+	}
+	items := make([]interface{}, 0, 10000)
+	for i := 0; i < 10000; i++ {
+		items = append(items, 20*i+1)
+	}
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		list := NewConcurrentList(WithSorting(func(i, j interface{}) bool {
+			return i.(int) < j.(int)
+		}))
+		list.PushSorted(existing)
+		b.StartTimer()
+
+		push(list, items)
+	}
+}
+
+func BenchmarkPushPresorted(b *testing.B) {
+	benchmarkPushPresorted(b, func(list *ConcurrentList, items []interface{}) {
+		list.Push(items...)
+	})
+}
+
+func BenchmarkPushSorted(b *testing.B) {
+	benchmarkPushPresorted(b, func(list *ConcurrentList, items []interface{}) {
+		list.PushSorted(items)
+	})
+}
@@ -0,0 +1,36 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRank(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push("a", "b", "c")
+
+	rank, ok := list.Rank(func(item interface{}) bool { return item == "c" })
+	require.True(t, ok)
+	require.Equal(t, 2, rank)
+
+	_, ok = list.Rank(func(item interface{}) bool { return item == "d" })
+	require.False(t, ok)
+
+	// Items with a higher priority move ahead
+	sorted := NewPriorityList(func(item interface{}) int { return item.(int) })
+	sorted.Push(10, 20, 30)
+	isTwenty := func(item interface{}) bool { return item == 20 }
+	rank, ok = sorted.Rank(isTwenty)
+	require.True(t, ok)
+	require.Equal(t, 1, rank)
+
+	sorted.Push(5)
+	rank, ok = sorted.Rank(isTwenty)
+	require.True(t, ok)
+	require.Equal(t, 2, rank)
+
+	sorted.Push(25)
+	rank, _ = sorted.Rank(isTwenty)
+	require.Equal(t, 2, rank)
+}
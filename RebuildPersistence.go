@@ -0,0 +1,156 @@
+package concurrentList
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// RebuildPersistence reconciles the directory of WithPersistence with the items in memory (e.g. after operations were skipped
+// or dropped): the files of all current items are written again, afterwards all other files holding an item are deleted.
+// Files which do not hold an item (and subdirectories which are no shards of WithShardedPersistence) are left alone.
+// If writing fails, nothing is deleted. The list is locked until all files are written (pending operations of
+// WithAsyncPersistence are done first).
+// Returns the first error which occurred and ErrPersistenceDisabled if WithPersistence is not used
+func (l *ConcurrentList) RebuildPersistence() error {
+	if !l.persistFiles() {
+		return ErrPersistenceDisabled
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.rebuildPersistence()
+}
+
+// MigratePersistenceNaming replaces the fileNameFunc of WithPersistence (e.g. after changing the naming scheme between versions)
+// and rewrites the directory accordingly (see RebuildPersistence): the files of all items are written again with their new
+// names, the ones with the old names are deleted afterwards. Returns the first error which occurred and ErrPersistenceDisabled if WithPersistence is not used
+func (l *ConcurrentList) MigratePersistenceNaming(fileNameFunc func(item interface{}) string) error {
+	if !l.persistFiles() {
+		return ErrPersistenceDisabled
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	// Pending operations still refer to the old names
+	l.flushPersistenceLocked()
+	l.loadAll()
+	l.opts.persistFileNameFunc = &fileNameFunc
+	return l.rebuildPersistence()
+}
+
+// SuspendPersistence stops writing and deleting files of WithPersistence and WithPersistenceBackend (e.g. for speeding up
+// a bulk import) until ResumePersistence is called. Items which are added or removed in the meantime only change the list in memory
+func (l *ConcurrentList) SuspendPersistence() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.persistSuspended = true
+}
+
+// ResumePersistence writes and deletes files again after SuspendPersistence and reconciles the directory of WithPersistence
+// with the items in memory (see RebuildPersistence), returning its error.
+// ATTENTION: Backends of WithPersistenceBackend are not reconciled, changes while persistence was suspended are missing there
+func (l *ConcurrentList) ResumePersistence() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if !l.persistSuspended {
+		return nil
+	}
+	l.persistSuspended = false
+	if !l.persistFiles() {
+		return nil
+	}
+	return l.rebuildPersistence()
+}
+
+// internal helper function for rewriting the directory of WithPersistence from memory. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) rebuildPersistence() error {
+	l.flushPersistenceLocked()
+	l.loadAll()
+
+	err := os.MkdirAll(l.opts.persistRootPath, 0755)
+	if err != nil {
+		return err
+	}
+
+	if l.persistRefs != nil {
+		l.persistRefs = map[string]int{}
+	}
+	var first error
+	written := map[string]bool{}
+	for _, item := range l.data {
+		op := l.persistenceOperation(item.value, false)
+		op.sequence = l.persistedSequence(item)
+		written[op.itemPath] = true
+		if !op.file {
+			continue
+		}
+		err = l.persistenceCreateFile(op.item, op.itemPath, op.sequence)
+		if err != nil {
+			l.handleError(err)
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	if first != nil {
+		return first
+	}
+
+	return l.persistenceDeleteStale(l.opts.persistRootPath, l.opts.persistShardFunc != nil, written)
+}
+
+// internal helper function for deleting the files in dir which hold an item, but were not written (see RebuildPersistence).
+// Shards which are empty afterwards are deleted as well. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) persistenceDeleteStale(dir string, sharded bool, written map[string]bool) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		itemPath := filepath.Join(dir, file.Name())
+		if sharded && file.IsDir() {
+			err = l.persistenceDeleteStale(itemPath, false, written)
+			if err != nil {
+				return err
+			}
+			// Fails if anything is left in the shard
+			_ = os.Remove(itemPath)
+			continue
+		}
+
+		if !file.Mode().IsRegular() || written[itemPath] || !l.persistenceHoldsItem(itemPath) {
+			continue
+		}
+		err = os.Remove(itemPath)
+		if err != nil {
+			return err
+		}
+		if l.persistVersions != nil {
+			l.persistVersionsLock.Lock()
+			delete(l.persistVersions, itemPath)
+			l.persistVersionsLock.Unlock()
+		}
+	}
+	return nil
+}
+
+// internal helper function for checking if a file holds an item of the list, i.e. it would be reconstructed from it
+func (l *ConcurrentList) persistenceHoldsItem(itemPath string) bool {
+	marshaled, err := ioutil.ReadFile(itemPath)
+	if err != nil {
+		return false
+	}
+
+	// Records of WithVersioning and WithSorting
+	if _, ok := persistenceParseRecord(marshaled); ok {
+		return true
+	}
+	_, err = l.persistenceUnmarshal(marshaled)
+	return err == nil
+}
@@ -0,0 +1,79 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildPersistence(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestRebuildPersistence")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}), WithAsyncPersistence(100))
+	for i := 0; i < 100; i++ {
+		list.Push(i)
+	}
+	list.DeleteWithFilter(func(item interface{}) bool { return item.(int) >= 10 })
+
+	// A stray file which holds an item which is not in the list anymore
+	require.NoError(t, list.FlushPersistence())
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "stray"), []byte("1000"), 0644))
+
+	// Files and directories which do not hold items are not touched
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "subdirectory"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "subdirectory", "5"), []byte("5"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "README"), []byte("not an item"), 0644))
+
+	require.NoError(t, list.RebuildPersistence())
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	names := []string{}
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	sort.Strings(names)
+	require.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "README", "subdirectory"}, names)
+	_, err = os.Stat(filepath.Join(tempDir, "subdirectory", "5"))
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(filepath.Join(tempDir, "subdirectory")))
+	require.NoError(t, os.Remove(filepath.Join(tempDir, "README")))
+	require.NoError(t, list.checkInvariants())
+
+	// The rebuilt files can be reconstructed
+	list = NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	require.Equal(t, 10, list.Length())
+
+	require.Equal(t, ErrPersistenceDisabled, NewConcurrentList().RebuildPersistence())
+}
+
+func TestRebuildPersistenceFailure(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestRebuildPersistenceFailure")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	list.Push(1, 2)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "3"), []byte("3"), 0644))
+
+	// An item which cannot be written: nothing is deleted
+	list.Push(make(chan int))
+	require.Error(t, list.RebuildPersistence())
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+}
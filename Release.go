@@ -0,0 +1,7 @@
+//go:build !debug
+// +build !debug
+
+package concurrentList
+
+// See Debug.go
+const debug = false
@@ -0,0 +1,48 @@
+package concurrentList
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemove(t *testing.T) {
+	type test struct {
+		ID   string
+		Data string
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "TestRemove")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, test{}, func(item interface{}) string {
+		return item.(test).ID
+	}))
+
+	list.Push(test{ID: "1", Data: "keep"})
+	list.Push(test{ID: "2", Data: "remove"})
+	list.Push(test{ID: "3", Data: "keep"})
+	list.Push(test{ID: "4", Data: "remove"})
+
+	equal := func(a, b interface{}) bool {
+		return a.(test).Data == b.(test).Data
+	}
+
+	require.Equal(t, 2, list.Remove(test{Data: "remove"}, equal))
+	require.Equal(t, []interface{}{
+		test{ID: "1", Data: "keep"},
+		test{ID: "3", Data: "keep"},
+	}, list.GetWithFilter(func(item interface{}) bool { return true }))
+
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	require.Equal(t, 0, list.Remove(test{Data: "remove"}, equal))
+}
@@ -0,0 +1,55 @@
+package concurrentList
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemovedItemsAreCollectable(t *testing.T) {
+	type test struct {
+		payload []byte
+	}
+
+	removals := map[string]func(list *ConcurrentList){
+		"Shift": func(list *ConcurrentList) {
+			_, err := list.Shift()
+			require.NoError(t, err)
+		},
+		"RemoveAt": func(list *ConcurrentList) {
+			_, err := list.RemoveAt(0)
+			require.NoError(t, err)
+		},
+		"DeleteWithFilter": func(list *ConcurrentList) {
+			list.DeleteWithFilter(func(item interface{}) bool {
+				_, ok := item.(*test)
+				return ok
+			})
+		},
+	}
+
+	for name, remove := range removals {
+		list := NewConcurrentList()
+		collected := int32(0)
+		item := &test{payload: make([]byte, 1024)}
+		runtime.SetFinalizer(item, func(*test) {
+			atomic.AddInt32(&collected, 1)
+		})
+		list.Push(item, "remaining1", "remaining2")
+		item = nil
+
+		// The backing array of the list stays in use, but must not keep the removed item from being collected
+		remove(list)
+		require.Equal(t, []interface{}{"remaining1", "remaining2"}, list.TopK(2), name)
+
+		for i := 0; i < 100 && atomic.LoadInt32(&collected) == 0; i++ {
+			runtime.GC()
+			time.Sleep(time.Millisecond)
+		}
+		require.Equal(t, int32(1), atomic.LoadInt32(&collected), name)
+		runtime.KeepAlive(list)
+	}
+}
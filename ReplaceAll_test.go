@@ -0,0 +1,57 @@
+package concurrentList
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceAll(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestReplaceAll")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}), WithSorting(func(i, j interface{}) bool {
+		return i.(int) < j.(int)
+	}))
+	list.Push(1, 2, 3)
+
+	list.ReplaceAll([]interface{}{30, 10, 20, 40})
+	require.Empty(t, list.Errors())
+	require.Equal(t, []interface{}{10, 20, 30, 40}, list.GetWithFilter(func(item interface{}) bool { return true }))
+
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	fileNames := []string{}
+	for _, file := range files {
+		fileNames = append(fileNames, file.Name())
+	}
+	require.ElementsMatch(t, []string{"10", "20", "30", "40"}, fileNames)
+
+	// Waiting consumers are woken up
+	list.ReplaceAll([]interface{}{})
+	require.Equal(t, 0, list.Length())
+	done := make(chan interface{})
+	go func() {
+		item, _ := list.GetNext(context.Background())
+		done <- item
+	}()
+	for {
+		if _, registered := list.debug(); registered == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	list.ReplaceAll([]interface{}{50})
+	require.Equal(t, 50, <-done)
+}
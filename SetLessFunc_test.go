@@ -0,0 +1,55 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLessFunc(t *testing.T) {
+	type test struct {
+		name     string
+		priority int
+	}
+
+	list := NewConcurrentList(WithSorting(func(i, j interface{}) bool {
+		return i.(test).priority < j.(test).priority
+	}))
+	list.Push(test{name: "b", priority: 1}, test{name: "c", priority: 3}, test{name: "a", priority: 2})
+	require.Equal(t, []interface{}{test{name: "b", priority: 1}, test{name: "a", priority: 2}, test{name: "c", priority: 3}}, list.TopK(3))
+
+	// Sort by name instead
+	list.SetLessFunc(func(i, j interface{}) bool {
+		return i.(test).name < j.(test).name
+	})
+	item, err := list.GetNext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, test{name: "a", priority: 2}, item)
+
+	// Turn sorting off: current order is kept, pushed items are appended
+	list.SetLessFunc(nil)
+	list.Push(test{name: "0", priority: 0})
+	require.Equal(t, []interface{}{test{name: "b", priority: 1}, test{name: "c", priority: 3}, test{name: "0", priority: 0}}, list.TopK(3))
+	require.NoError(t, list.InsertAt(0, test{name: "first", priority: 10}))
+
+	// Turn sorting on again
+	list.SetLessFunc(func(i, j interface{}) bool {
+		return i.(test).priority > j.(test).priority
+	})
+	item, err = list.GetNext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, test{name: "first", priority: 10}, item)
+	require.Equal(t, ErrSortingEnabled, list.InsertAt(0, test{}))
+
+	// Replaces the priority of priority lists
+	priorityList := NewPriorityList(func(item interface{}) int {
+		return item.(int)
+	})
+	priorityList.Push(1, 2, 3)
+	priorityList.SetLessFunc(func(i, j interface{}) bool {
+		return i.(int) > j.(int)
+	})
+	require.Equal(t, []interface{}{3, 2, 1}, priorityList.TopK(3))
+	require.Equal(t, ErrPriorityDisabled, priorityList.PushWithPriority(0, 0))
+}
@@ -0,0 +1,43 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShedLowest(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestShedLowest")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewMaxPriorityList(func(item interface{}) int {
+		return item.(int)
+	}, WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	list.Push(300, 100, 500, 200, 400)
+
+	require.Equal(t, []interface{}{200, 100}, list.ShedLowest(2))
+	require.Equal(t, []interface{}{500, 400, 300}, list.snapshot())
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	require.NoError(t, list.checkInvariants())
+
+	// Shedding more items than there are empties the list
+	require.Equal(t, []interface{}{500, 400, 300}, list.ShedLowest(10))
+	require.Empty(t, list.ShedLowest(1))
+
+	// Without sorting the newest items are shed
+	unsorted := NewConcurrentList()
+	unsorted.Push(1, 2, 3)
+	require.Equal(t, []interface{}{3}, unsorted.ShedLowest(1))
+	require.Empty(t, unsorted.ShedLowest(0))
+	require.Equal(t, []interface{}{1, 2}, unsorted.snapshot())
+}
@@ -0,0 +1,28 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	list := NewConcurrentList()
+	require.Empty(t, list.Snapshot())
+	require.Empty(t, list.ReverseSnapshot())
+
+	list.Push(1, 2, 3, 4)
+	snapshot := list.Snapshot()
+	require.Equal(t, []interface{}{1, 2, 3, 4}, snapshot)
+
+	reversed := make([]interface{}, 0, len(snapshot))
+	for i := len(snapshot) - 1; i >= 0; i-- {
+		reversed = append(reversed, snapshot[i])
+	}
+	require.Equal(t, reversed, list.ReverseSnapshot())
+
+	// Snapshots are copies
+	snapshot[0] = 100
+	require.Equal(t, []interface{}{1, 2, 3, 4}, list.Snapshot())
+	require.Equal(t, 4, list.Length())
+}
@@ -0,0 +1,78 @@
+package concurrentList
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortStabilityAcrossReload(t *testing.T) {
+	type test struct {
+		ID       string
+		Priority int
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "TestSortStabilityAcrossReload")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	opts := func() []ConcurrentListOption {
+		return []ConcurrentListOption{
+			WithPersistence(tempDir, test{}, func(item interface{}) string {
+				return item.(test).ID
+			}),
+			WithSorting(func(i, j interface{}) bool {
+				return i.(test).Priority < j.(test).Priority
+			}),
+		}
+	}
+
+	// The order of the file names differs from the order the items are pushed in
+	list := NewConcurrentList(opts()...)
+	for _, id := range []string{"m", "c", "x", "a", "k", "b"} {
+		priority := 2
+		if id == "k" || id == "b" {
+			priority = 1
+		}
+		list.Push(test{ID: id, Priority: priority})
+	}
+
+	expected := []interface{}{
+		test{ID: "k", Priority: 1},
+		test{ID: "b", Priority: 1},
+		test{ID: "m", Priority: 2},
+		test{ID: "c", Priority: 2},
+		test{ID: "x", Priority: 2},
+		test{ID: "a", Priority: 2},
+	}
+	require.Equal(t, expected, list.TopK(6))
+
+	// Equal items keep their relative order when the list is reconstructed
+	reloaded := NewConcurrentList(opts()...)
+	require.Equal(t, expected, reloaded.TopK(6))
+
+	reloaded = NewConcurrentList(append(opts(), WithLazyPersistenceLoad())...)
+	require.Equal(t, expected, reloaded.TopK(6))
+
+	// The order does not depend on the modification times of the files
+	modified := time.Now().Add(-time.Hour)
+	for _, id := range []string{"m", "c", "x", "a", "k", "b"} {
+		require.NoError(t, os.Chtimes(filepath.Join(tempDir, id), modified, modified))
+	}
+	reloaded = NewConcurrentList(opts()...)
+	require.Equal(t, expected, reloaded.TopK(6))
+
+	// Items pushed after reconstructing come after the equal ones which were reconstructed
+	reloaded.Push(test{ID: "d", Priority: 1})
+	require.Equal(t, test{ID: "d", Priority: 1}, reloaded.TopK(3)[2])
+
+	// The files can be reconstructed by a list which is not sorted as well
+	unsorted := NewConcurrentList(opts()[0])
+	require.Equal(t, 7, unsorted.Length())
+	require.ElementsMatch(t, append(expected, test{ID: "d", Priority: 1}), unsorted.TopK(7))
+}
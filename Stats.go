@@ -0,0 +1,47 @@
+package concurrentList
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the state of a ConcurrentList, see Stats()
+type Stats struct {
+	// Current number of items
+	Length int
+	// Number of goroutines blocked in GetNext
+	Waiting int
+	// Number of items pushed since the list was created
+	TotalPushed int64
+	// Number of items taken from the head of the list (Shift, GetNext) since the list was created
+	TotalShifted int64
+	// Number of items removed because their ttl expired
+	TTLEvictions int64
+	// How long the first item has been waiting since it was pushed (0 if the list is empty)
+	OldestAge time.Duration
+	// Whether the list is persisted
+	PersistenceEnabled bool
+	// Maximum number of items (0 if the list is unbounded, see WithCapacity)
+	Capacity int
+}
+
+// Stats returns a snapshot of the state of the list. All values are captured at the same time
+func (l *ConcurrentList) Stats() Stats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.applyDeferredSort()
+
+	stats := Stats{
+		Length:             len(l.data),
+		Waiting:            len(l.waiters),
+		TotalPushed:        atomic.LoadInt64(l.totalPushed),
+		TotalShifted:       atomic.LoadInt64(l.totalShifted),
+		TTLEvictions:       atomic.LoadInt64(l.ttlEvictions),
+		PersistenceEnabled: l.opts.persistChanges,
+		Capacity:           l.opts.capacity,
+	}
+	if len(l.data) > 0 {
+		stats.OldestAge = time.Since(l.data[0].pushedAt)
+	}
+	return stats
+}
@@ -0,0 +1,53 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	list := NewConcurrentList(WithTTL(50*time.Millisecond, 10*time.Millisecond, func(item interface{}) time.Time {
+		if item.(int) < 0 {
+			return time.Time{}
+		}
+		return time.Now()
+	}))
+	require.Equal(t, Stats{}, list.Stats())
+
+	// Expired immediately
+	list.Push(-1, -2)
+	time.Sleep(30 * time.Millisecond)
+
+	list.Push(1, 2, 3)
+	_, err := list.Shift()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err = list.GetNext(ctx)
+	require.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+	stats := list.Stats()
+	require.Equal(t, 1, stats.Length)
+	require.Equal(t, 0, stats.Waiting)
+	require.Equal(t, int64(5), stats.TotalPushed)
+	require.Equal(t, int64(2), stats.TotalShifted)
+	require.Equal(t, int64(2), stats.TTLEvictions)
+	require.GreaterOrEqual(t, int64(stats.OldestAge), int64(20*time.Millisecond))
+	require.False(t, stats.PersistenceEnabled)
+
+	// Waiting consumers
+	_, err = list.Shift()
+	require.NoError(t, err)
+	go func() {
+		_, _ = list.GetNext(ctx)
+	}()
+	for list.Stats().Waiting != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	require.Equal(t, time.Duration(0), list.Stats().OldestAge)
+}
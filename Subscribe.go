@@ -0,0 +1,106 @@
+package concurrentList
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Subscribe returns a channel which receives all items of the list in the order GetNext would return them.
+// Every item is only received by a single subscriber (or GetNext call). The channel is closed once the context
+// expires or the list is closed. An item which was taken from the list, but not received yet when the context expires,
+// is put back to the head of the list
+func (l *ConcurrentList) Subscribe(ctx context.Context) <-chan interface{} {
+	return l.SubscribeBuffered(ctx, 0)
+}
+
+// SubscribeBuffered works like Subscribe, but prefetches up to bufferSize items into the buffer of the channel,
+// so a fast consumer does not need to wait for the lock of the list for every single item.
+// ATTENTION: Items which are in the buffer when the context expires are not put back: they can still be received
+// from the (closed) channel, but they are lost if the consumer stops receiving
+func (l *ConcurrentList) SubscribeBuffered(ctx context.Context, bufferSize int) <-chan interface{} {
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+
+	items := make(chan interface{}, bufferSize)
+	go l.subscription(ctx, items)
+	return items
+}
+
+// subscription moves items from the list into the channel of a subscriber until the context expires or the list is closed
+func (l *ConcurrentList) subscription(ctx context.Context, items chan interface{}) {
+	defer close(items)
+
+	for {
+		prefetched, err := l.prefetch(ctx, cap(items)-len(items))
+		if err != nil {
+			return
+		}
+
+		for i, item := range prefetched {
+			select {
+			case items <- item.value:
+			case <-ctx.Done():
+				l.requeue(prefetched[i:])
+				return
+			}
+		}
+	}
+}
+
+// internal helper function for waiting until items are available and taking up to n (but at least one) of them at once
+func (l *ConcurrentList) prefetch(ctx context.Context, n int) ([]*listItem, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err := l.waitForItem(ctx); err != nil {
+		return nil, err
+	}
+
+	// Do not take the items which are reserved for waiters which were woken up before
+	if available := len(l.data) - l.wokenWaiters; n > available {
+		n = available
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	prefetched := make([]*listItem, 0, n)
+	for len(prefetched) < n {
+		item, err := l.shiftItem()
+		if err != nil {
+			break
+		}
+		prefetched = append(prefetched, item)
+	}
+	if len(prefetched) == 0 {
+		return nil, ErrEmptyList
+	}
+	return prefetched, nil
+}
+
+// internal helper function for putting items which were taken from the list back to its head (in the same order)
+func (l *ConcurrentList) requeue(items []*listItem) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	previousLength := len(l.data)
+	l.data = append(append(make([]*listItem, 0, len(items)+len(l.data)), items...), l.data...)
+	l.dataChanged()
+	atomic.AddInt64(l.totalShifted, -int64(len(items)))
+	atomic.AddInt64(l.totalConsumed, -int64(len(items)))
+	l.sortData()
+
+	if l.opts.persistChanges {
+		for _, item := range items {
+			l.persistCreate(item)
+		}
+	}
+
+	l.signalNonEmpty(previousLength)
+	l.wakeForItems(len(items))
+}
@@ -0,0 +1,89 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribe(t *testing.T) {
+	list := NewConcurrentList()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	items := list.Subscribe(ctx)
+	for i := 0; i < 10; i++ {
+		list.Push(i)
+	}
+	for i := 0; i < 10; i++ {
+		require.Equal(t, i, <-items)
+	}
+
+	// The item which was taken, but not received is put back
+	list.Push(10, 11)
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	_, ok := <-items
+	require.False(t, ok)
+	require.Equal(t, []interface{}{10, 11}, list.TopK(2))
+}
+
+func TestSubscribeBuffered(t *testing.T) {
+	length := 100
+	list := NewConcurrentList()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	items := list.SubscribeBuffered(ctx, 10)
+	for i := 0; i < length; i++ {
+		list.Push(i)
+	}
+	for i := 0; i < length/2; i++ {
+		require.Equal(t, i, <-items)
+	}
+
+	// Buffered items can be drained after cancelling, all others are still in the list
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	expected := length / 2
+	for item := range items {
+		require.Equal(t, expected, item)
+		expected++
+	}
+	require.LessOrEqual(t, expected-length/2, 10)
+	require.Equal(t, length-expected, list.Length())
+	if list.Length() > 0 {
+		first, err := list.Peek()
+		require.NoError(t, err)
+		require.Equal(t, expected, first)
+	}
+
+	// Closing the list terminates the subscription
+	items = list.SubscribeBuffered(context.Background(), 10)
+	require.NoError(t, list.Close())
+	for range items {
+	}
+}
+
+func benchmarkSubscribe(b *testing.B, bufferSize int) {
+	list := NewConcurrentList()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := 0; i < b.N; i++ {
+		list.Push(i)
+	}
+	b.ResetTimer()
+	items := list.SubscribeBuffered(ctx, bufferSize)
+	for i := 0; i < b.N; i++ {
+		<-items
+	}
+}
+
+func BenchmarkSubscribe(b *testing.B) {
+	benchmarkSubscribe(b, 0)
+}
+
+func BenchmarkSubscribeBuffered(b *testing.B) {
+	benchmarkSubscribe(b, 100)
+}
@@ -0,0 +1,54 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuspendPersistence(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestSuspendPersistence")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	list.Push(-1)
+
+	// Nothing is written or deleted while persistence is suspended
+	list.SuspendPersistence()
+	for i := 0; i < 1000; i++ {
+		list.Push(i)
+	}
+	_, err := list.Shift()
+	require.NoError(t, err)
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	// Resuming reconciles the directory with memory
+	require.NoError(t, list.ResumePersistence())
+	files, err = ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 1000)
+	require.NoError(t, list.checkInvariants())
+
+	// Persistence works as usual afterwards
+	_, err = list.Shift()
+	require.NoError(t, err)
+	files, err = ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 999)
+	require.NoError(t, list.ResumePersistence())
+
+	list = NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	require.Equal(t, 999, list.Length())
+}
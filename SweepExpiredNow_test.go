@@ -0,0 +1,31 @@
+package concurrentList
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepExpiredNow(t *testing.T) {
+	type test struct {
+		ID      string
+		AddedAt time.Time
+	}
+
+	// The background check does not run during the test
+	list := NewConcurrentList(WithTTL(time.Minute, time.Hour, func(item interface{}) time.Time {
+		return item.(test).AddedAt
+	}))
+	defer list.Close()
+
+	expired := []interface{}{test{ID: "a", AddedAt: time.Now().Add(-time.Hour)}, test{ID: "b", AddedAt: time.Now().Add(-2 * time.Minute)}}
+	list.Push(expired[0], test{ID: "fresh", AddedAt: time.Now()}, expired[1])
+
+	require.Equal(t, expired, list.SweepExpiredNow())
+	require.Equal(t, 1, list.Length())
+	require.Equal(t, int64(2), list.EvictedCount())
+	require.Empty(t, list.SweepExpiredNow())
+
+	require.Nil(t, NewConcurrentList().SweepExpiredNow())
+}
@@ -0,0 +1,35 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopK(t *testing.T) {
+	type test struct {
+		item     string
+		priority int
+	}
+
+	list := NewConcurrentList(WithSorting(func(i, j interface{}) bool {
+		return i.(test).priority > j.(test).priority
+	}))
+	require.Empty(t, list.TopK(3))
+
+	for _, priority := range []int{200, 500, 100, 400, 300} {
+		list.Push(test{item: "item", priority: priority})
+	}
+
+	topK := list.TopK(3)
+	require.Len(t, topK, 3)
+	require.Equal(t, 500, topK[0].(test).priority)
+	require.Equal(t, 400, topK[1].(test).priority)
+	require.Equal(t, 300, topK[2].(test).priority)
+
+	// The list is not modified
+	require.Equal(t, 5, list.Length())
+	require.Len(t, list.TopK(10), 5)
+	require.Empty(t, list.TopK(0))
+	require.Empty(t, list.TopK(-1))
+}
@@ -0,0 +1,41 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTotalCounters(t *testing.T) {
+	list := NewConcurrentList()
+	require.Equal(t, int64(0), list.TotalPushed())
+	require.Equal(t, int64(0), list.TotalConsumed())
+
+	list.Push(1, 2, 3, 4, 5)
+	list.Push(6)
+	_, err := list.Shift()
+	require.NoError(t, err)
+	_, err = list.GetNext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(6), list.TotalPushed())
+	require.Equal(t, int64(2), list.TotalConsumed())
+
+	// Every way of removing items counts
+	list.DeleteWithFilter(func(item interface{}) bool { return item == 3 })
+	_, err = list.Pop()
+	require.NoError(t, err)
+	require.Equal(t, int64(4), list.TotalConsumed())
+
+	// The counters survive draining the list
+	list.Drain()
+	require.Equal(t, 0, list.Length())
+	require.Equal(t, int64(6), list.TotalPushed())
+	require.Equal(t, int64(6), list.TotalConsumed())
+
+	list.ReplaceAll([]interface{}{7, 8})
+	list.ReplaceAll([]interface{}{9})
+	require.Equal(t, int64(9), list.TotalPushed())
+	require.Equal(t, int64(8), list.TotalConsumed())
+	require.Equal(t, list.TotalPushed()-list.TotalConsumed(), int64(list.Length()))
+}
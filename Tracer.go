@@ -0,0 +1,25 @@
+package concurrentList
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer can be passed to WithTracer for observing how long GetNext calls are blocked
+// (e.g. for emitting spans without coupling the list to a specific tracing library)
+// Both methods are called by the goroutine calling GetNext, without holding the lock of the list
+type Tracer interface {
+	// OnGetNextStart is called before GetNext starts waiting for an item
+	OnGetNextStart(ctx context.Context)
+	// OnGetNextEnd is called once GetNext returns, with the time it waited and the error it returns (if any)
+	OnGetNextEnd(ctx context.Context, waited time.Duration, err error)
+}
+
+func (l *ConcurrentList) traceGetNextStart(ctx context.Context) time.Time {
+	l.opts.tracer.OnGetNextStart(ctx)
+	return time.Now()
+}
+
+func (l *ConcurrentList) traceGetNextEnd(ctx context.Context, started time.Time, err error) {
+	l.opts.tracer.OnGetNextEnd(ctx, time.Since(started), err)
+}
@@ -0,0 +1,44 @@
+package concurrentList
+
+// internal helper function for collecting the keys of all items (see WithUniqueness). Returns nil without WithUniqueness.
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) uniqueKeys() map[string]bool {
+	if l.opts.uniqueKeyFunc == nil {
+		return nil
+	}
+
+	l.loadAll()
+	keys := make(map[string]bool, len(l.data))
+	for _, item := range l.data {
+		keys[(*l.opts.uniqueKeyFunc)(item.value)] = true
+	}
+	return keys
+}
+
+// internal helper function for removing all but the first item per key from a reconstructed list and deleting their files
+// (see WithUniqueness). the caller needs to make sure the collection is locked
+func (l *ConcurrentList) persistenceDedup() {
+	if l.opts.uniqueKeyFunc == nil {
+		return
+	}
+
+	l.loadAll()
+	kept := map[string]*listItem{}
+	unique := make([]*listItem, 0, len(l.data))
+	for _, item := range l.data {
+		key := (*l.opts.uniqueKeyFunc)(item.value)
+		first, ok := kept[key]
+		if !ok {
+			kept[key] = item
+			unique = append(unique, item)
+			continue
+		}
+
+		// The file of the kept item must stay if both items map to the same file
+		if !l.persistFiles() || l.persistencePath(first.value) != l.persistencePath(item.value) {
+			l.persistDelete(item.value)
+		}
+	}
+	l.data = unique
+	l.dataChanged()
+}
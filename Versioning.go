@@ -0,0 +1,54 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+)
+
+// internal helper function for getting the next version of the file of an item (see persistenceWrapRecord).
+// The version is higher than both the one of the file on disk (which might have been written by another list) and the one
+// this list knows of
+func (l *ConcurrentList) persistenceNextVersion(itemPath string) uint64 {
+	l.persistVersionsLock.Lock()
+	defer l.persistVersionsLock.Unlock()
+
+	version := l.persistVersions[itemPath]
+	if onDisk, err := persistenceFileVersion(itemPath); err == nil && onDisk > version {
+		version = onDisk
+	}
+	version++
+	l.persistVersions[itemPath] = version
+	return version
+}
+
+// internal helper function for remembering the version of a file which was read (0 for files written without WithVersioning)
+func (l *ConcurrentList) persistenceRememberVersion(itemPath string, version uint64) {
+	l.persistVersionsLock.Lock()
+	defer l.persistVersionsLock.Unlock()
+
+	l.persistVersions[itemPath] = version
+}
+
+// internal helper function for checking that the file of an item was not written by anyone else since this list wrote
+// or read it before it is deleted. Returns ErrVersionConflict if it was, otherwise its version is forgotten
+func (l *ConcurrentList) persistenceForgetVersion(itemPath string) error {
+	l.persistVersionsLock.Lock()
+	defer l.persistVersionsLock.Unlock()
+
+	// If the file cannot be read, deleting it reports the error (if any)
+	if onDisk, err := persistenceFileVersion(itemPath); err == nil && onDisk > l.persistVersions[itemPath] {
+		return fmt.Errorf("%w: %s", ErrVersionConflict, itemPath)
+	}
+	delete(l.persistVersions, itemPath)
+	return nil
+}
+
+// the version of the record in a file of WithPersistence
+func persistenceFileVersion(itemPath string) (uint64, error) {
+	contents, err := ioutil.ReadFile(itemPath)
+	if err != nil {
+		return 0, err
+	}
+	record, _ := persistenceParseRecord(contents)
+	return record.Version, nil
+}
@@ -0,0 +1,42 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitConsumed(t *testing.T) {
+	list := NewConcurrentList()
+	isTagged := func(item interface{}) bool { return item == "tagged" }
+
+	// Nothing to wait for
+	require.NoError(t, list.WaitConsumed(context.Background(), isTagged))
+
+	list.Push("a", "tagged", "b")
+	consumed := make(chan interface{}, 2)
+	go func() {
+		for i := 0; i < 2; i++ {
+			time.Sleep(10 * time.Millisecond)
+			item, err := list.GetNext(context.Background())
+			require.NoError(t, err)
+			consumed <- item
+		}
+	}()
+
+	// Returns once the tagged item was consumed, but not before
+	require.NoError(t, list.WaitConsumed(context.Background(), isTagged))
+	_, found := list.PeekWhere(isTagged)
+	require.False(t, found)
+	require.Equal(t, []interface{}{"b"}, list.GetWithFilter(func(item interface{}) bool { return true }))
+	require.Equal(t, "a", <-consumed)
+	require.Equal(t, "tagged", <-consumed)
+
+	// Times out while a matching item remains
+	list.Push("tagged")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, list.WaitConsumed(ctx, isTagged))
+}
@@ -0,0 +1,52 @@
+package concurrentList
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Upper bounds of the buckets of WaitTimeHistogram, the last bucket holds all longer waits
+var waitTimeBuckets = []time.Duration{
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+	time.Minute,
+	time.Duration(math.MaxInt64),
+}
+
+// WaitTimeHistogram returns how long calls of GetNext and GetNextOrHighWater waited for an item, including calls whose context expired.
+// Every call which gets to wait is recorded exactly once, other ways of waiting (e.g. PopNext, GetNextBatchWindow or Subscribe) are not.
+// Every key is the upper bound of a bucket (1ms, 10ms, 100ms, 1s, 10s, 1m and math.MaxInt64 for all longer waits),
+// the value is the number of calls which waited longer than the previous bound but at most as long as the key
+func (l *ConcurrentList) WaitTimeHistogram() map[time.Duration]int64 {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	histogram := make(map[time.Duration]int64, len(waitTimeBuckets))
+	for i, bound := range waitTimeBuckets {
+		histogram[bound] = l.waitTimes[i]
+	}
+	return histogram
+}
+
+// internal helper function for waiting until an item is available (see waitForItem) and recording how long it took.
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) waitForItemRecorded(ctx context.Context) error {
+	started := time.Now()
+	defer func() { l.recordWaitTime(time.Since(started)) }()
+
+	return l.waitForItem(ctx)
+}
+
+// internal helper function for recording how long a call waited for an item. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) recordWaitTime(waited time.Duration) {
+	for i, bound := range waitTimeBuckets {
+		if waited <= bound {
+			l.waitTimes[i]++
+			return
+		}
+	}
+}
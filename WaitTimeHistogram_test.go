@@ -0,0 +1,63 @@
+package concurrentList
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitTimeHistogram(t *testing.T) {
+	list := NewConcurrentList()
+
+	// No wait
+	list.Push(1)
+	_, err := list.GetNext(context.Background())
+	require.NoError(t, err)
+
+	// Wait for a delayed producer
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		list.Push(2)
+	}()
+	_, err = list.GetNext(context.Background())
+	require.NoError(t, err)
+
+	// Waits which end because the context expired are recorded as well
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	_, err = list.GetNext(ctx)
+	require.Equal(t, ErrEmptyList, err)
+
+	require.Equal(t, map[time.Duration]int64{
+		time.Millisecond:             1,
+		10 * time.Millisecond:        0,
+		100 * time.Millisecond:       1,
+		time.Second:                  1,
+		10 * time.Second:             0,
+		time.Minute:                  0,
+		time.Duration(math.MaxInt64): 0,
+	}, list.WaitTimeHistogram())
+}
+
+func TestWaitTimeHistogramOnlyGetNext(t *testing.T) {
+	list := NewConcurrentList()
+
+	// Other ways of waiting are not recorded
+	list.Push(1, 2, 3)
+	_, err := list.PopNext(context.Background())
+	require.NoError(t, err)
+	_, err = list.GetNextBatchWindow(context.Background(), 50*time.Millisecond, 10*time.Millisecond, 10)
+	require.NoError(t, err)
+	for _, count := range list.WaitTimeHistogram() {
+		require.Zero(t, count)
+	}
+
+	list.Push(4)
+	_, atHighWater, err := list.GetNextOrHighWater(context.Background(), 10)
+	require.NoError(t, err)
+	require.False(t, atHighWater)
+	require.Equal(t, int64(1), list.WaitTimeHistogram()[time.Millisecond])
+}
@@ -0,0 +1,147 @@
+package concurrentList
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// waiter is a single goroutine which is waiting for an item
+// Every waiter has its own channel, so it can be woken up without waking up any other waiter
+type waiter struct {
+	wake chan struct{}
+
+	// Whether an item is reserved for the waiter once it is woken up (see wakeWaiters and wakeAllWaiters)
+	reserved bool
+}
+
+// internal helper function for waiting until woken up by a push or until the context expires.
+// the caller needs to make sure the collection is locked. It is unlocked while waiting and locked again once wait returns
+// Waiters are woken up in the order they started waiting
+func (l *ConcurrentList) wait(ctx context.Context) error {
+	w := l.newWaiter()
+	l.waiters = append(l.waiters, w)
+	l.lock.Unlock()
+
+	select {
+	case <-w.wake:
+		l.lock.Lock()
+		if w.reserved {
+			l.wokenWaiters--
+		}
+		l.recycleWaiter(w)
+		return nil
+	case <-ctx.Done():
+		l.lock.Lock()
+		if !l.removeWaiter(w) {
+			// We were woken up just as the context expired: pass it on so it is not lost for the others
+			<-w.wake
+			if w.reserved {
+				l.wokenWaiters--
+				l.wakeWaiters(1)
+			}
+		}
+		l.recycleWaiter(w)
+		return ctx.Err()
+	}
+}
+
+// internal helper function for getting a waiter. With WithFastPath idle waiters are reused instead of allocating new ones.
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) newWaiter() *waiter {
+	if last := len(l.idleWaiters) - 1; last >= 0 {
+		w := l.idleWaiters[last]
+		l.idleWaiters[last] = nil
+		l.idleWaiters = l.idleWaiters[:last]
+		w.reserved = false
+		return w
+	}
+	return &waiter{wake: make(chan struct{}, 1)}
+}
+
+// internal helper function for keeping a waiter which is done waiting for reuse (see WithFastPath).
+// the waiter must neither be registered nor have a pending wake up. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) recycleWaiter(w *waiter) {
+	if l.opts.fastPath {
+		l.idleWaiters = append(l.idleWaiters, w)
+	}
+}
+
+// internal helper function for waking up the n longest waiting waiters. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) wakeWaiters(n int) {
+	for ; n > 0 && len(l.waiters) > 0; n-- {
+		w := l.waiters[0]
+		if l.opts.fastPath {
+			// Shifting the remaining waiters keeps the capacity of the slice, so registering does not allocate again
+			copy(l.waiters, l.waiters[1:])
+			l.waiters[len(l.waiters)-1] = nil
+			l.waiters = l.waiters[:len(l.waiters)-1]
+		} else {
+			l.waiters[0] = nil
+			l.waiters = l.waiters[1:]
+		}
+		w.reserved = true
+		l.wokenWaiters++
+		atomic.AddInt64(l.wakeUps, 1)
+		w.wake <- struct{}{}
+	}
+}
+
+// internal helper function for waking up waiters for n added items according to WithWakeStrategy. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) wakeForItems(n int) {
+	l.wakeWaiters(n)
+	if l.opts.wakeStrategy == WakeBroadcast {
+		l.wakeAllWaiters()
+	}
+}
+
+// internal helper function for waking up all waiters without reserving items for them: they check for an item
+// and wait again if there is none. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) wakeAllWaiters() {
+	for i, w := range l.waiters {
+		l.waiters[i] = nil
+		w.reserved = false
+		atomic.AddInt64(l.wakeUps, 1)
+		w.wake <- struct{}{}
+	}
+	l.waiters = l.waiters[:0]
+}
+
+// internal helper function for removing a waiter which was not woken up. the caller needs to make sure the collection is locked
+// returns false if the waiter was already woken up
+func (l *ConcurrentList) removeWaiter(w *waiter) bool {
+	for i, registered := range l.waiters {
+		if registered == w {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// internal helper function for waiting until items are added to or removed from the list or until the context expires.
+// the caller needs to make sure the collection is locked. It is unlocked while waiting and locked again once waitChange returns
+// In contrast to wait, all goroutines waiting for a change are woken up at once
+func (l *ConcurrentList) waitChange(ctx context.Context) error {
+	if l.changed == nil {
+		l.changed = make(chan struct{})
+	}
+	changed := l.changed
+	l.lock.Unlock()
+
+	select {
+	case <-changed:
+		l.lock.Lock()
+		return nil
+	case <-ctx.Done():
+		l.lock.Lock()
+		return ctx.Err()
+	}
+}
+
+// internal helper function for waking up everyone waiting for a change. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) notifyChange() {
+	if l.changed != nil {
+		close(l.changed)
+		l.changed = nil
+	}
+}
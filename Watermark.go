@@ -0,0 +1,103 @@
+package concurrentList
+
+import "context"
+
+// WatermarkEvent is emitted when the length of the list crosses a watermark (see WatermarkEvents)
+type WatermarkEvent int
+
+const (
+	// HighWater is emitted when the length rises above the high watermark
+	HighWater WatermarkEvent = iota
+	// LowWater is emitted when the length falls below the low watermark after HighWater was emitted
+	LowWater
+)
+
+// watermark is a single registration of WatermarkEvents
+type watermark struct {
+	high   int
+	low    int
+	above  bool
+	events chan WatermarkEvent
+}
+
+// WatermarkEvents returns a channel which receives HighWater when the length of the list rises above high and LowWater
+// when it falls below low afterwards (e.g. for autoscaling consumers). Events are edge-triggered: they alternate and are
+// only emitted on crossings, not for every change. If the list is already above high when WatermarkEvents is called,
+// the next event is LowWater. Sending never blocks the list: only the latest event is kept until it is received, if the
+// length crosses back before that, the pending event is withdrawn instead of sending the opposite one. So the events
+// still alternate and the last one received always reflects the current state.
+// The channel is closed once the context expires or the list is closed
+func (l *ConcurrentList) WatermarkEvents(ctx context.Context, high, low int) <-chan WatermarkEvent {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	w := &watermark{
+		high:   high,
+		low:    low,
+		above:  len(l.data) > high,
+		events: make(chan WatermarkEvent, 1),
+	}
+	if l.closed || ctx.Err() != nil {
+		close(w.events)
+		return w.events
+	}
+	l.watermarks = append(l.watermarks, w)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			l.lock.Lock()
+			l.removeWatermark(w)
+			l.lock.Unlock()
+		case <-l.done:
+			// All channels are closed by Close
+		}
+	}()
+	return w.events
+}
+
+// internal helper function for emitting the events of all watermarks which were crossed. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) checkWatermarks() {
+	length := len(l.data)
+	for _, w := range l.watermarks {
+		var event WatermarkEvent
+		switch {
+		case !w.above && length > w.high:
+			w.above = true
+			event = HighWater
+		case w.above && length < w.low:
+			w.above = false
+			event = LowWater
+		default:
+			continue
+		}
+
+		// Only this function sends, so there is room for the event unless the previous one was not received yet.
+		// That one is outdated by now: the receiver is left with the state it knew before
+		select {
+		case <-w.events:
+		default:
+			w.events <- event
+		}
+	}
+}
+
+// internal helper function for removing a single watermark and closing its channel (unless this happened already).
+// the caller needs to make sure the collection is locked
+func (l *ConcurrentList) removeWatermark(w *watermark) {
+	for i, registered := range l.watermarks {
+		if registered == w {
+			l.watermarks = append(l.watermarks[:i], l.watermarks[i+1:]...)
+			close(w.events)
+			return
+		}
+	}
+}
+
+// internal helper function for closing the channels of all watermarks. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) closeWatermarks() {
+	for _, w := range l.watermarks {
+		close(w.events)
+	}
+	l.watermarks = nil
+}
@@ -0,0 +1,94 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatermarkEvents(t *testing.T) {
+	list := NewConcurrentList()
+	events := list.WatermarkEvents(context.Background(), 5, 2)
+
+	received := func() []WatermarkEvent {
+		all := []WatermarkEvent{}
+		for {
+			select {
+			case event := <-events:
+				all = append(all, event)
+			default:
+				return all
+			}
+		}
+	}
+
+	// Reaching the high watermark is not a crossing
+	list.Push(1, 2, 3, 4, 5)
+	require.Empty(t, received())
+
+	// Crossing it is, further growth is not
+	list.Push(6)
+	list.Push(7, 8)
+	require.Equal(t, []WatermarkEvent{HighWater}, received())
+
+	// Falling below high, but not below low emits nothing
+	for list.Length() > 2 {
+		_, err := list.Shift()
+		require.NoError(t, err)
+	}
+	require.Empty(t, received())
+
+	_, err := list.Shift()
+	require.NoError(t, err)
+	list.Clear()
+	require.Equal(t, []WatermarkEvent{LowWater}, received())
+
+	list.Push(1, 2, 3, 4, 5, 6)
+	require.Equal(t, []WatermarkEvent{HighWater}, received())
+	list.Drain()
+	require.Equal(t, []WatermarkEvent{LowWater}, received())
+
+	// Crossing back before the pending event was received withdraws it
+	list.Push(1, 2, 3, 4, 5, 6)
+	list.Drain()
+	require.Empty(t, received())
+	list.Push(1, 2, 3, 4, 5, 6)
+	list.Drain()
+	list.Push(1, 2, 3, 4, 5, 6)
+	require.Equal(t, []WatermarkEvent{HighWater}, received())
+
+	// Closing the list closes the channel
+	require.NoError(t, list.Close())
+	_, ok := <-events
+	require.False(t, ok)
+}
+
+func TestWatermarkEventsAlreadyAbove(t *testing.T) {
+	list := NewConcurrentList()
+	list.Push(1, 2, 3)
+	events := list.WatermarkEvents(context.Background(), 1, 1)
+
+	list.Push(4)
+	list.Drain()
+	require.Equal(t, LowWater, <-events)
+}
+
+func TestWatermarkEventsContext(t *testing.T) {
+	list := NewConcurrentList()
+	defer list.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	events := list.WatermarkEvents(ctx, 1, 1)
+	other := list.WatermarkEvents(context.Background(), 1, 1)
+
+	// Expiring the context closes the channel and removes the registration
+	cancel()
+	_, ok := <-events
+	require.False(t, ok)
+	list.lock.Lock()
+	require.Len(t, list.watermarks, 1)
+	list.lock.Unlock()
+
+	list.Push(1, 2)
+	require.Equal(t, HighWater, <-other)
+}
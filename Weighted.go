@@ -0,0 +1,84 @@
+package concurrentList
+
+import "context"
+
+// GetNextWeighted gets the next item in a weighted round-robin over the categories of the items (determined by categoryFunc),
+// so no category starves: while items of all categories are available, the share of every category is proportional to its
+// weight (e.g. weights 2 and 1 yield two items of the first category for every item of the second one, interleaved).
+// Within a category the order of the list is kept. Categories without a positive weight have a weight of 1.
+// Items are taken just like by GetNext (e.g. expired items of WithLazyExpiry are skipped).
+// Blocks until an item is available or the passed in context expires (ErrEmptyList).
+// The round-robin is kept across calls, all consumers of a list should use the same categoryFunc and weights
+func (l *ConcurrentList) GetNextWeighted(ctx context.Context, categoryFunc func(item interface{}) string, weights map[string]int) (interface{}, error) {
+	if ctx.Err() != nil {
+		return nil, ErrEmptyList
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for {
+		l.loadAll()
+
+		// Any item might be picked, so all expired items are dropped beforehand (see WithLazyExpiry)
+		for i := len(l.data) - 1; i >= 0; i-- {
+			l.dropExpiredAt(i)
+		}
+		if index := l.nextWeighted(categoryFunc, weights); index >= 0 {
+			return l.takeItem(index).value, nil
+		}
+
+		if l.closed {
+			return nil, ErrClosed
+		}
+		if err := l.waitChange(ctx); err != nil {
+			return nil, ErrEmptyList
+		}
+	}
+}
+
+// internal helper function for picking the index of the next item of GetNextWeighted (smooth weighted round-robin):
+// every category with items gains its weight as credit, the one with the most credit is picked and pays the
+// weights of all categories with items. returns -1 if the list is empty. the caller needs to make sure the collection is locked
+func (l *ConcurrentList) nextWeighted(categoryFunc func(item interface{}) string, weights map[string]int) int {
+	if l.weightedCredits == nil {
+		l.weightedCredits = map[string]int{}
+	}
+
+	// The first item of every category
+	heads := map[string]int{}
+	categories := []string{}
+	for i, item := range l.data {
+		category := categoryFunc(item.value)
+		if _, ok := heads[category]; !ok {
+			heads[category] = i
+			categories = append(categories, category)
+		}
+	}
+	if len(categories) == 0 {
+		return -1
+	}
+
+	total := 0
+	picked := categories[0]
+	for _, category := range categories {
+		weight := weights[category]
+		if weight < 1 {
+			weight = 1
+		}
+		total += weight
+		l.weightedCredits[category] += weight
+		if l.weightedCredits[category] > l.weightedCredits[picked] {
+			picked = category
+		}
+	}
+	l.weightedCredits[picked] -= total
+
+	// Categories without items do not keep their credit
+	for category := range l.weightedCredits {
+		if _, ok := heads[category]; !ok {
+			delete(l.weightedCredits, category)
+		}
+	}
+	return heads[picked]
+}
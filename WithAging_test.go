@@ -0,0 +1,51 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type agingTest struct {
+	Name     string
+	Priority float64
+	PushedAt time.Time
+}
+
+func TestWithAging(t *testing.T) {
+	list := NewConcurrentList(WithAging(func(item interface{}) float64 {
+		return item.(agingTest).Priority
+	}, 1, func(item interface{}) time.Time {
+		return item.(agingTest).PushedAt
+	}))
+
+	now := time.Now()
+	list.Push(agingTest{Name: "low", Priority: 1, PushedAt: now.Add(-time.Minute)})
+
+	// Newer items with a higher priority are consumed first as long as the low priority item did not age enough
+	list.Push(agingTest{Name: "high", Priority: 100, PushedAt: now})
+	item, err := list.GetNext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "high", item.(agingTest).Name)
+
+	// After aging for a minute at a rate of one per second the low priority item is consumed ahead of newer high priority items
+	list.Push(agingTest{Name: "newer", Priority: 50, PushedAt: now}, agingTest{Name: "highest", Priority: 100, PushedAt: now})
+	for _, expected := range []string{"highest", "low", "newer"} {
+		item, err = list.GetNext(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, expected, item.(agingTest).Name)
+	}
+
+	// Without aging priorities are compared as they are
+	list = NewConcurrentList(WithAging(func(item interface{}) float64 {
+		return item.(agingTest).Priority
+	}, 0, func(item interface{}) time.Time {
+		return item.(agingTest).PushedAt
+	}))
+	list.Push(agingTest{Name: "low", Priority: 1, PushedAt: now.Add(-time.Hour)}, agingTest{Name: "high", Priority: 2, PushedAt: now})
+	item, err = list.GetNext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "high", item.(agingTest).Name)
+}
@@ -0,0 +1,131 @@
+package concurrentList
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAsyncPersistence(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithAsyncPersistence")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}), WithAsyncPersistence(1000))
+
+	for i := 0; i < 100; i++ {
+		list.Push(i)
+	}
+	require.NoError(t, list.FlushPersistence())
+
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 100)
+
+	// Files are never deleted before they are written
+	for i := 100; i < 200; i++ {
+		list.Push(i)
+		_, err = list.GetNext(context.Background())
+		require.NoError(t, err)
+	}
+	list.DeleteWithFilter(func(item interface{}) bool { return item.(int) < 150 })
+	require.NoError(t, list.FlushPersistence())
+
+	require.Empty(t, list.Errors())
+	files, err = ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 50)
+
+	// The persisted list can be reconstructed
+	list = NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}), WithAsyncPersistence(10))
+	require.Equal(t, 50, list.Length())
+}
+
+func benchmarkPushWithPersistence(b *testing.B, opts ...ConcurrentListOption) {
+	tempDir, err := ioutil.TempDir("", "BenchmarkPushWithPersistence")
+	require.NoError(b, err)
+	defer func() {
+		require.NoError(b, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(append([]ConcurrentListOption{WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	})}, opts...)...)
+
+	// Every iteration waits until the item is on disk, but only the time spent in Push is reported as latency
+	pushDuration := time.Duration(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		started := time.Now()
+		list.Push(i)
+		pushDuration += time.Since(started)
+		require.NoError(b, list.FlushPersistence())
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(pushDuration.Nanoseconds())/float64(b.N), "push-ns/op")
+}
+
+func BenchmarkPushWithPersistence(b *testing.B) {
+	benchmarkPushWithPersistence(b)
+}
+
+func BenchmarkPushWithAsyncPersistence(b *testing.B) {
+	benchmarkPushWithPersistence(b, WithAsyncPersistence(1))
+}
+
+func TestWithAsyncPersistenceQueueFull(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithAsyncPersistenceQueueFull")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	// The errorHandler uses the list, which must not deadlock the worker
+	var list *ConcurrentList
+	errs := make(chan error, 100)
+	backend := &slowBackend{release: make(chan struct{})}
+	list = NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}, func(err error) {
+		list.Length()
+		errs <- err
+	}), WithPersistenceBackend(backend), WithAsyncPersistence(1))
+
+	// The worker hangs, but Push never waits for it: operations which do not fit into the queue are dropped
+	started := time.Now()
+	for i := 0; i < 10; i++ {
+		list.Push(i)
+	}
+	require.True(t, time.Since(started) < 500*time.Millisecond)
+	require.Equal(t, 10, list.Length())
+	require.False(t, list.PersistenceHealthy())
+
+	close(backend.release)
+	require.NoError(t, list.FlushPersistence())
+	require.ErrorIs(t, <-errs, ErrPersistenceQueueFull)
+
+	// The directory is restored from memory while the errorHandler is free to use the list
+	require.NoError(t, list.RebuildPersistence())
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 10)
+	require.NoError(t, list.Close())
+}
+
+func TestFlushPersistenceWithoutPersistence(t *testing.T) {
+	list := NewConcurrentList(WithAsyncPersistence(10))
+	require.Equal(t, ErrPersistenceDisabled, list.FlushPersistence())
+}
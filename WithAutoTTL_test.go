@@ -0,0 +1,32 @@
+package concurrentList
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAutoTTL(t *testing.T) {
+	list := NewConcurrentList(
+		WithAutoTTL(100*time.Millisecond, 10*time.Millisecond),
+		WithSorting(func(i, j interface{}) bool {
+			return i.(int) < j.(int)
+		}),
+	)
+
+	list.Push(3)
+	list.Push(2)
+	time.Sleep(60 * time.Millisecond)
+
+	// Sorts to the front, but is the newest item
+	list.Push(1)
+	require.Equal(t, 3, list.Length())
+
+	// The two older items expire, the newest one survives although it was sorted in front of them
+	time.Sleep(80 * time.Millisecond)
+	require.Equal(t, []interface{}{1}, list.GetWithFilter(func(item interface{}) bool { return true }))
+
+	time.Sleep(80 * time.Millisecond)
+	require.Equal(t, 0, list.Length())
+}
@@ -0,0 +1,71 @@
+package concurrentList
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryPush(t *testing.T) {
+	list := NewConcurrentList(WithCapacity(3))
+
+	accepted, err := list.TryPush(1, 2, 3, 4, 5)
+	require.Equal(t, ErrListFull, err)
+	require.Equal(t, 3, accepted)
+	require.Equal(t, []interface{}{1, 2, 3}, list.TopK(5))
+
+	accepted, err = list.TryPush(6)
+	require.Equal(t, ErrListFull, err)
+	require.Equal(t, 0, accepted)
+	require.Equal(t, ErrListFull, list.InsertAt(0, 6))
+
+	_, err = list.Shift()
+	require.NoError(t, err)
+	accepted, err = list.TryPush(6)
+	require.NoError(t, err)
+	require.Equal(t, 1, accepted)
+	require.Equal(t, 3, list.Stats().Capacity)
+
+	// Unbounded lists accept everything
+	accepted, err = NewConcurrentList().TryPush(1, 2, 3, 4, 5)
+	require.NoError(t, err)
+	require.Equal(t, 5, accepted)
+}
+
+func TestWithCapacity(t *testing.T) {
+	list := NewConcurrentList(WithCapacity(2))
+	list.Push(1, 2)
+
+	// Push blocks until there is space for all items
+	pushed := make(chan struct{})
+	go func() {
+		list.Push(3, 4)
+		close(pushed)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	_, err := list.Shift()
+	require.NoError(t, err)
+	select {
+	case <-pushed:
+		require.FailNow(t, "pushed although only a single item fits")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	_, err = list.Shift()
+	require.NoError(t, err)
+	select {
+	case <-pushed:
+	case <-time.After(time.Second):
+		require.FailNow(t, "push did not continue once there was enough space")
+	}
+	require.Equal(t, []interface{}{3, 4}, list.TopK(2))
+
+	// Closing the list stops waiting
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, list.Close())
+	}()
+	list.Push(5)
+}
@@ -0,0 +1,48 @@
+package concurrentList
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithComparator(t *testing.T) {
+	type test struct {
+		name     string
+		priority int
+	}
+
+	// Sorted by priority, items with equal priorities are sorted by name
+	list := NewConcurrentList(WithComparator(func(a, b interface{}) int {
+		switch {
+		case a.(test).priority < b.(test).priority:
+			return -1
+		case a.(test).priority > b.(test).priority:
+			return 1
+		}
+		return strings.Compare(a.(test).name, b.(test).name)
+	}))
+	list.Push(test{name: "b", priority: 2}, test{name: "a", priority: 2}, test{name: "c", priority: 1})
+	require.Equal(t, []interface{}{
+		test{name: "c", priority: 1},
+		test{name: "a", priority: 2},
+		test{name: "b", priority: 2},
+	}, list.snapshot())
+	require.True(t, list.IsSorted())
+
+	// Equal items keep the order they were pushed in
+	stable := NewConcurrentList(WithComparator(func(a, b interface{}) int {
+		return a.(test).priority - b.(test).priority
+	}))
+	stable.Push(test{name: "first", priority: 1}, test{name: "second", priority: 0})
+	stable.Push(test{name: "third", priority: 1})
+	stable.Push(test{name: "fourth", priority: 0})
+	require.Equal(t, []interface{}{
+		test{name: "second", priority: 0},
+		test{name: "fourth", priority: 0},
+		test{name: "first", priority: 1},
+		test{name: "third", priority: 1},
+	}, stable.snapshot())
+	require.NoError(t, stable.checkInvariants())
+}
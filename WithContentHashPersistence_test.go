@@ -0,0 +1,58 @@
+package concurrentList
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithContentHashPersistence(t *testing.T) {
+	type test struct {
+		Data string
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "TestWithContentHashPersistence")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithContentHashPersistence(tempDir, test{}))
+
+	// Identical items share one file
+	list.Push(test{Data: "identical"}, test{Data: "identical"})
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	hash := sha256.Sum256([]byte(`{"Data":"identical"}`))
+	require.Equal(t, hex.EncodeToString(hash[:]), files[0].Name())
+
+	list.Push(test{Data: "other"})
+	files, err = ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	// The shared file is kept until the last identical item is removed
+	_, err = list.Shift()
+	require.NoError(t, err)
+	files, err = ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	_, err = list.Shift()
+	require.NoError(t, err)
+	files, err = ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Empty(t, list.Errors())
+
+	// Reconstruct
+	list, err = NewConcurrentListChecked(WithContentHashPersistence(tempDir, test{}))
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{test{Data: "other"}}, list.GetWithFilter(func(item interface{}) bool { return true }))
+}
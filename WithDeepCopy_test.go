@@ -0,0 +1,33 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDeepCopy(t *testing.T) {
+	type test struct {
+		Values []int
+	}
+
+	list := NewConcurrentList(WithDeepCopy(func(item interface{}) interface{} {
+		original := item.(test)
+		return test{Values: append([]int{}, original.Values...)}
+	}))
+
+	item := test{Values: []int{1, 2, 3}}
+	list.Push(item)
+	item.Values[0] = 100
+
+	other := test{Values: []int{4}}
+	require.NoError(t, list.InsertAt(0, other))
+	other.Values[0] = 400
+
+	queued, err := list.Shift()
+	require.NoError(t, err)
+	require.Equal(t, test{Values: []int{4}}, queued)
+	queued, err = list.Shift()
+	require.NoError(t, err)
+	require.Equal(t, test{Values: []int{1, 2, 3}}, queued)
+}
@@ -0,0 +1,48 @@
+package concurrentList
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDeferredSort(t *testing.T) {
+	list := NewConcurrentList(WithSorting(func(i, j interface{}) bool {
+		return i.(int) < j.(int)
+	}), WithDeferredSort(100*time.Millisecond))
+
+	// A burst is sorted once after it settled
+	for i := 100; i > 0; i-- {
+		list.Push(i)
+	}
+	require.Equal(t, int64(0), atomic.LoadInt64(list.sorts))
+	require.Eventually(t, func() bool { return atomic.LoadInt64(list.sorts) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(150 * time.Millisecond)
+	require.Equal(t, int64(1), atomic.LoadInt64(list.sorts))
+	require.NoError(t, list.checkInvariants())
+
+	for i := 1; i <= 100; i++ {
+		item, err := list.GetNext(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, i, item)
+	}
+
+	// Reading before the burst settled sorts right away
+	list.Push(3, 1, 2)
+	item, err := list.Peek()
+	require.NoError(t, err)
+	require.Equal(t, 1, item)
+	list.Push(0)
+	require.Equal(t, []interface{}{0, 1, 2, 3}, list.GetWithFilter(func(item interface{}) bool { return true }))
+	item, err = list.GetNext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, item)
+
+	// Nothing left to sort once the quiet duration is over
+	sorts := atomic.LoadInt64(list.sorts)
+	time.Sleep(150 * time.Millisecond)
+	require.Equal(t, sorts, atomic.LoadInt64(list.sorts))
+}
@@ -0,0 +1,82 @@
+package concurrentList
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFastPath(t *testing.T) {
+	list := NewConcurrentList(WithFastPath())
+
+	// Items are still consumed in order when waiters are reused
+	results := make(chan interface{}, 100)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			item, err := list.GetNext(context.Background())
+			require.NoError(t, err)
+			results <- item
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		list.Push(i)
+		if i%10 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	wg.Wait()
+	close(results)
+	expected := 0
+	for item := range results {
+		require.Equal(t, expected, item)
+		expected++
+	}
+
+	// Cancelled waits can be reused as well
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err := list.GetNext(ctx)
+		cancel()
+		require.Equal(t, ErrEmptyList, err)
+	}
+	list.Push("afterCancel")
+	item, err := list.GetNext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "afterCancel", item)
+}
+
+func benchmarkGetNextBlocking(b *testing.B, opts ...ConcurrentListOption) {
+	list := NewConcurrentList(opts...)
+	done := make(chan struct{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	go func() {
+		defer close(done)
+		for i := 0; i < b.N; i++ {
+			_, _ = list.GetNext(context.Background())
+		}
+	}()
+	for i := 0; i < b.N; i++ {
+		// Only push once the consumer is waiting, so every GetNext has to wait
+		for _, waiting := list.debug(); waiting != 1; _, waiting = list.debug() {
+			runtime.Gosched()
+		}
+		list.Push(i)
+	}
+	<-done
+}
+
+func BenchmarkGetNextBlocking(b *testing.B) {
+	benchmarkGetNextBlocking(b)
+}
+
+func BenchmarkGetNextBlockingWithFastPath(b *testing.B) {
+	benchmarkGetNextBlocking(b, WithFastPath())
+}
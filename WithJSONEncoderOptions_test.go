@@ -0,0 +1,44 @@
+package concurrentList
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithJSONEncoderOptions(t *testing.T) {
+	type test struct {
+		Name string
+		Data string
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "TestWithJSONEncoderOptions")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	opts := []ConcurrentListOption{
+		WithPersistence(tempDir, test{}, func(item interface{}) string {
+			return item.(test).Name
+		}),
+		WithJSONEncoderOptions(func(encoder *json.Encoder) {
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+		}),
+	}
+	list := NewConcurrentList(opts...)
+	list.Push(test{Name: "first", Data: "<b>bold</b>"})
+
+	contents, err := ioutil.ReadFile(filepath.Join(tempDir, "first"))
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"Name\": \"first\",\n  \"Data\": \"<b>bold</b>\"\n}", string(contents))
+
+	// Reconstruct
+	list, err = NewConcurrentListChecked(opts...)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{test{Name: "first", Data: "<b>bold</b>"}}, list.snapshot())
+}
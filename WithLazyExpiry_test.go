@@ -0,0 +1,71 @@
+package concurrentList
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLazyExpiry(t *testing.T) {
+	type test struct {
+		Time time.Time
+		Data string
+	}
+
+	list := NewConcurrentList(WithLazyExpiry(30*time.Millisecond, func(item interface{}) time.Time {
+		return item.(test).Time
+	}))
+
+	list.Push(test{Time: time.Now(), Data: "expired"})
+	time.Sleep(50 * time.Millisecond)
+
+	// No background routine removes the item
+	require.Equal(t, 1, list.Length())
+
+	_, err := list.Shift()
+	require.Equal(t, ErrEmptyList, err)
+	require.Equal(t, 0, list.Length())
+
+	// GetNext skips expired items and returns the next fresh one
+	list.Push(test{Time: time.Now(), Data: "expired"})
+	time.Sleep(50 * time.Millisecond)
+	list.Push(test{Time: time.Now(), Data: "fresh"})
+
+	item, err := list.GetNext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "fresh", item.(test).Data)
+
+	// If only expired items are left, GetNext keeps waiting
+	list.Push(test{Time: time.Now(), Data: "expired"})
+	time.Sleep(50 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = list.GetNext(ctx)
+	require.Equal(t, ErrEmptyList, err)
+	require.Equal(t, 0, list.Length())
+	require.Equal(t, int64(3), list.Stats().TTLEvictions)
+}
+
+func TestWithLazyExpiryPeek(t *testing.T) {
+	list := NewConcurrentList(WithLazyExpiry(30*time.Millisecond, func(item interface{}) time.Time {
+		return item.(time.Time)
+	}))
+
+	// Peek skips expired items just like GetNext
+	list.Push(time.Now())
+	time.Sleep(50 * time.Millisecond)
+	_, err := list.Peek()
+	require.Equal(t, ErrEmptyList, err)
+	require.Equal(t, 0, list.Length())
+
+	list.Push(time.Now())
+	time.Sleep(50 * time.Millisecond)
+	fresh := time.Now()
+	list.Push(fresh)
+	item, err := list.PeekWithTimeout(10 * time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, fresh, item)
+	require.Equal(t, 1, list.Length())
+}
@@ -0,0 +1,80 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// counts how often a lazyTest was reconstructed
+var lazyTestUnmarshaled int64
+
+type lazyTest int
+
+func (t lazyTest) MarshalBinary() ([]byte, error) {
+	return []byte(strconv.Itoa(int(t))), nil
+}
+
+func (t *lazyTest) UnmarshalBinary(data []byte) error {
+	atomic.AddInt64(&lazyTestUnmarshaled, 1)
+	value, err := strconv.Atoi(string(data))
+	*t = lazyTest(value)
+	return err
+}
+
+func TestWithLazyPersistenceLoad(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithLazyPersistenceLoad")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	fileNameFunc := func(item interface{}) string {
+		return fmt.Sprintf("%05d", item)
+	}
+
+	list := NewConcurrentList(WithPersistence(tempDir, lazyTest(0), fileNameFunc))
+	for i := 0; i < 1000; i++ {
+		list.Push(lazyTest(i))
+	}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "99999"), []byte("corrupt"), 0644))
+
+	// Nothing is reconstructed when the list is created
+	atomic.StoreInt64(&lazyTestUnmarshaled, 0)
+	list, err := NewConcurrentListChecked(WithPersistence(tempDir, lazyTest(0), fileNameFunc), WithLazyPersistenceLoad())
+	require.NoError(t, err)
+	require.Equal(t, 1001, list.Length())
+	require.Equal(t, int64(0), atomic.LoadInt64(&lazyTestUnmarshaled))
+
+	// Consuming only reconstructs the first item
+	item, err := list.Shift()
+	require.NoError(t, err)
+	require.Equal(t, lazyTest(0), item)
+	item, err = list.Peek()
+	require.NoError(t, err)
+	require.Equal(t, lazyTest(1), item)
+	require.Equal(t, int64(2), atomic.LoadInt64(&lazyTestUnmarshaled))
+
+	// Looking at all items reconstructs all of them, the corrupt file is dropped
+	items := list.GetWithFilter(func(item interface{}) bool {
+		return item.(lazyTest) >= 998
+	})
+	require.Equal(t, []interface{}{lazyTest(998), lazyTest(999)}, items)
+	require.Equal(t, int64(1001), atomic.LoadInt64(&lazyTestUnmarshaled))
+	require.Equal(t, 999, list.Length())
+	require.Len(t, list.Errors(), 1)
+
+	// Files of reconstructed items are deleted as usual
+	list.DeleteWithFilter(func(item interface{}) bool {
+		return true
+	})
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
@@ -0,0 +1,54 @@
+package concurrentList
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLoadDuplicatePolicy(t *testing.T) {
+	type test struct {
+		ID string
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "TestWithLoadDuplicatePolicy")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	// "x-old" was written by a previous fileNameFunc and holds the same item as "x"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "x"), []byte(`{"ID":"x"}`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "x-old"), []byte(`{"ID":"x"}`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "y"), []byte(`{"ID":"y"}`), 0644))
+
+	persistence := WithPersistence(tempDir, test{}, func(item interface{}) string {
+		return item.(test).ID
+	})
+
+	list, err := NewConcurrentListChecked(persistence)
+	require.NoError(t, err)
+	require.Equal(t, 3, list.Length())
+
+	list, err = NewConcurrentListChecked(persistence, WithLoadDuplicatePolicy(DuplicatesKeepAll))
+	require.NoError(t, err)
+	require.Equal(t, 3, list.Length())
+
+	_, err = NewConcurrentListChecked(persistence, WithLoadDuplicatePolicy(DuplicatesError))
+	require.True(t, errors.Is(err, ErrDuplicateItem))
+
+	// The duplicate file is deleted
+	list, err = NewConcurrentListChecked(persistence, WithLoadDuplicatePolicy(DuplicatesKeepFirst))
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{test{ID: "x"}, test{ID: "y"}}, list.TopK(2))
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	_, err = NewConcurrentListChecked(persistence, WithLoadDuplicatePolicy(DuplicatesError))
+	require.NoError(t, err)
+}
@@ -0,0 +1,125 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func persistedBytesOnDisk(t *testing.T, dir string) int64 {
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	total := int64(0)
+	for _, file := range files {
+		total += file.Size()
+	}
+	return total
+}
+
+func TestWithMaxPersistedBytes(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithMaxPersistedBytes")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	discarded := []interface{}{}
+	// Every item takes 102 bytes (a json string of 100 characters)
+	list := NewConcurrentList(WithPersistence(tempDir, "", func(item interface{}) string {
+		return item.(string)[:1]
+	}), WithMaxPersistedBytes(350), WithOnDiscard(func(item interface{}) {
+		discarded = append(discarded, item)
+	}))
+
+	for _, prefix := range []string{"a", "b", "c", "d", "e"} {
+		list.Push(prefix + strings.Repeat("x", 99))
+		require.LessOrEqual(t, persistedBytesOnDisk(t, tempDir), int64(350))
+	}
+
+	// The oldest items were dropped to make room
+	require.Equal(t, 3, list.Length())
+	require.Len(t, discarded, 2)
+	require.True(t, strings.HasPrefix(discarded[0].(string), "a"))
+	require.True(t, strings.HasPrefix(discarded[1].(string), "b"))
+	item, err := list.Peek()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(item.(string), "c"))
+	require.Empty(t, list.Errors())
+
+	// Items which do not even fit into an empty list are rejected
+	accepted, err := list.TryPush("f"+strings.Repeat("x", 99), "g"+strings.Repeat("x", 400))
+	require.Equal(t, 1, accepted)
+	require.ErrorIs(t, err, ErrListFull)
+	list.Push("h" + strings.Repeat("x", 400))
+	errs := list.Errors()
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], ErrListFull)
+	require.Equal(t, 3, list.Length())
+	require.LessOrEqual(t, persistedBytesOnDisk(t, tempDir), int64(350))
+
+	// Push rejects all items if any of them does not fit, nothing is dropped for them
+	before := list.GetWithFilter(func(item interface{}) bool { return true })
+	discardedBefore := len(discarded)
+	list.Push("j"+strings.Repeat("x", 99), "k"+strings.Repeat("x", 400), "l"+strings.Repeat("x", 99))
+	errs = list.Errors()
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], ErrListFull)
+	require.Equal(t, before, list.GetWithFilter(func(item interface{}) bool { return true }))
+	require.Len(t, discarded, discardedBefore)
+
+	// The sizes of reconstructed items are known
+	list = NewConcurrentList(WithPersistence(tempDir, "", func(item interface{}) string {
+		return item.(string)[:1]
+	}), WithMaxPersistedBytes(350))
+	require.Empty(t, list.Errors())
+	require.Equal(t, 3, list.Length())
+	list.Push("i" + strings.Repeat("x", 99))
+	require.Equal(t, 3, list.Length())
+	require.LessOrEqual(t, persistedBytesOnDisk(t, tempDir), int64(350))
+}
+
+func TestWithMaxPersistedBytesLazy(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithMaxPersistedBytesLazy")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}))
+	list.Push(1000, 2000, 3000)
+
+	// Each item takes 4 bytes
+	list = NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}), WithLazyPersistenceLoad(), WithMaxPersistedBytes(12))
+	list.Push(4000)
+	require.Equal(t, int64(12), persistedBytesOnDisk(t, tempDir))
+	require.Equal(t, []interface{}{2000, 3000, 4000}, list.GetWithFilter(func(item interface{}) bool { return true }))
+	require.NoError(t, list.checkInvariants())
+}
+
+func TestWithMaxPersistedBytesSorted(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithMaxPersistedBytesSorted")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	// Each item takes 4 bytes
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}), WithSorting(func(i, j interface{}) bool {
+		return i.(int) < j.(int)
+	}), WithMaxPersistedBytes(12))
+	list.Push(3000, 1000, 2000)
+
+	list.PushSorted([]interface{}{1500, 2500})
+	require.Equal(t, []interface{}{1500, 2000, 2500}, list.GetWithFilter(func(item interface{}) bool { return true }))
+	require.True(t, list.PushReport(4000, 5000))
+	require.Equal(t, []interface{}{2500, 4000, 5000}, list.GetWithFilter(func(item interface{}) bool { return true }))
+	require.NoError(t, list.checkInvariants())
+}
@@ -0,0 +1,71 @@
+package concurrentList
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithOnDiscard(t *testing.T) {
+	discarded := []interface{}{}
+	list := NewConcurrentList(WithOnDiscard(func(item interface{}) {
+		discarded = append(discarded, item)
+	}))
+
+	list.Push(1, 2, 3)
+	list.Clear()
+	require.Equal(t, []interface{}{1, 2, 3}, discarded)
+	require.Equal(t, 0, list.Length())
+
+	list.Push(4, 5)
+	list.ReplaceAll([]interface{}{6})
+	require.Equal(t, []interface{}{1, 2, 3, 4, 5}, discarded)
+
+	// Items handed to a consumer are not discarded
+	list.Push(7)
+	_, err := list.Shift()
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{1, 2, 3, 4, 5}, discarded)
+
+	// Bulk removals are, even though the items are returned
+	require.Equal(t, []interface{}{7}, list.Drain())
+	require.Equal(t, []interface{}{1, 2, 3, 4, 5, 7}, discarded)
+	list.Push(8, 9)
+	require.Equal(t, []interface{}{8, 9}, list.DrainInto(nil))
+	require.Equal(t, []interface{}{1, 2, 3, 4, 5, 7, 8, 9}, discarded)
+}
+
+func TestWithOnDiscardTTL(t *testing.T) {
+	discardedLock := sync.Mutex{}
+	discarded := []interface{}{}
+	list := NewConcurrentList(WithTTL(10*time.Millisecond, 5*time.Millisecond, func(item interface{}) time.Time {
+		return item.(time.Time)
+	}), WithOnDiscard(func(item interface{}) {
+		discardedLock.Lock()
+		defer discardedLock.Unlock()
+		discarded = append(discarded, item)
+	}))
+	defer list.Close()
+
+	expired := time.Now().Add(-time.Hour)
+	list.Push(expired)
+	require.Eventually(t, func() bool {
+		discardedLock.Lock()
+		defer discardedLock.Unlock()
+		return len(discarded) == 1
+	}, time.Second, time.Millisecond)
+	require.Equal(t, expired, discarded[0])
+
+	lazy := []interface{}{}
+	list = NewConcurrentList(WithLazyExpiry(10*time.Millisecond, func(item interface{}) time.Time {
+		return item.(time.Time)
+	}), WithOnDiscard(func(item interface{}) {
+		lazy = append(lazy, item)
+	}))
+	list.Push(expired)
+	_, err := list.Shift()
+	require.ErrorIs(t, err, ErrEmptyList)
+	require.Equal(t, []interface{}{expired}, lazy)
+}
@@ -0,0 +1,29 @@
+package concurrentList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithOnPush(t *testing.T) {
+	var list *ConcurrentList
+	pushed := []interface{}{}
+	lengths := []int{}
+
+	list = NewConcurrentList(WithOnPush(func(item interface{}) {
+		pushed = append(pushed, item)
+		// The list must not be locked while the hook is running
+		lengths = append(lengths, len(list.GetWithFilter(func(item interface{}) bool { return true })))
+	}))
+
+	list.Push(1)
+	require.Equal(t, []interface{}{1}, pushed)
+
+	list.Push(2, 3, 4)
+	require.Equal(t, []interface{}{1, 2, 3, 4}, pushed)
+	require.Equal(t, []int{1, 4, 4, 4}, lengths)
+
+	list.Push()
+	require.Len(t, pushed, 4)
+}
@@ -0,0 +1,96 @@
+package concurrentList
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBackend keeps the persisted items in memory in the order they were stored
+type memoryBackend struct {
+	items []interface{}
+	fail  bool
+	lock  sync.Mutex
+}
+
+func (b *memoryBackend) Store(item interface{}) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.fail {
+		return errors.New("store failed")
+	}
+	b.items = append(b.items, item)
+	return nil
+}
+
+func (b *memoryBackend) Delete(item interface{}) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for i, stored := range b.items {
+		if stored == item {
+			b.items = append(b.items[:i], b.items[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (b *memoryBackend) Load() ([]interface{}, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]interface{}{}, b.items...), nil
+}
+
+func TestWithPersistenceBackend(t *testing.T) {
+	primary := &memoryBackend{}
+	mirror := &memoryBackend{}
+	list := NewConcurrentList(WithPersistenceBackend(primary), WithPersistenceBackend(mirror))
+
+	list.Push(1, 2, 3)
+	_, err := list.Shift()
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{2, 3}, primary.items)
+	require.Equal(t, []interface{}{2, 3}, mirror.items)
+
+	// Reconstructed from the first backend
+	mirror.items = nil
+	list = NewConcurrentList(WithPersistenceBackend(primary), WithPersistenceBackend(mirror))
+	require.Equal(t, []interface{}{2, 3}, list.GetWithFilter(func(item interface{}) bool { return true }))
+	_, ok := list.PersistenceDir()
+	require.False(t, ok)
+
+	// Failing backends are reported
+	mirror.fail = true
+	list.Push(4)
+	require.False(t, list.PersistenceHealthy())
+	require.Len(t, list.Errors(), 1)
+	require.Equal(t, []interface{}{2, 3, 4}, primary.items)
+}
+
+func TestWithPersistenceBackendMirror(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithPersistenceBackendMirror")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	mirror := &memoryBackend{}
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}), WithPersistenceBackend(mirror), WithAsyncPersistence(10))
+	list.Push(1, 2, 3)
+	list.DeleteWithFilter(func(item interface{}) bool { return item == 2 })
+	require.NoError(t, list.FlushPersistence())
+	require.Equal(t, []interface{}{1, 3}, mirror.items)
+	require.NoError(t, list.checkInvariants())
+
+	// Reconstructed from the directory
+	list = NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}), WithPersistenceBackend(&memoryBackend{}))
+	require.Equal(t, 2, list.Length())
+}
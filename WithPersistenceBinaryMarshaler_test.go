@@ -0,0 +1,60 @@
+package concurrentList
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type binaryTest struct {
+	ID   string
+	Data string
+}
+
+func (b binaryTest) MarshalBinary() ([]byte, error) {
+	return []byte(b.ID + "|" + b.Data), nil
+}
+
+func (b *binaryTest) UnmarshalBinary(data []byte) error {
+	parts := strings.SplitN(string(data), "|", 2)
+	if len(parts) != 2 {
+		return errors.New("invalid format")
+	}
+	b.ID = parts[0]
+	b.Data = parts[1]
+	return nil
+}
+
+func TestWithPersistenceBinaryMarshaler(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithPersistenceBinaryMarshaler")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	fileNameFunc := func(item interface{}) string {
+		return item.(binaryTest).ID
+	}
+
+	list := NewConcurrentList(WithPersistence(tempDir, binaryTest{}, fileNameFunc))
+	item := binaryTest{ID: "1", Data: "binary"}
+	list.Push(item)
+	require.Empty(t, list.Errors())
+
+	expected, err := item.MarshalBinary()
+	require.NoError(t, err)
+	persisted, err := ioutil.ReadFile(filepath.Join(tempDir, "1"))
+	require.NoError(t, err)
+	require.Equal(t, expected, persisted)
+
+	list, err = NewConcurrentListChecked(WithPersistence(tempDir, binaryTest{}, fileNameFunc))
+	require.NoError(t, err)
+	reconstructed, err := list.Shift()
+	require.NoError(t, err)
+	require.Equal(t, item, reconstructed)
+}
@@ -0,0 +1,56 @@
+package concurrentList
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPersistenceCircuitBreaker(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithPersistenceCircuitBreaker")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	errs := []error{}
+	opened := 0
+	list := NewConcurrentList(
+		WithPersistence(tempDir, 0, func(item interface{}) string {
+			return fmt.Sprint(item)
+		}, func(err error) {
+			errs = append(errs, err)
+		}),
+		WithPersistenceCircuitBreaker(3, 50*time.Millisecond, func() {
+			opened++
+		}),
+	)
+
+	// Every write fails once the directory is gone
+	require.NoError(t, os.RemoveAll(tempDir))
+	for i := 0; i < 10; i++ {
+		list.Push(i)
+	}
+	require.Len(t, errs, 3)
+	require.Equal(t, 1, opened)
+
+	// Writes are attempted again after the cooldown
+	time.Sleep(60 * time.Millisecond)
+	for i := 10; i < 20; i++ {
+		list.Push(i)
+	}
+	require.Len(t, errs, 6)
+	require.Equal(t, 2, opened)
+
+	// Successful writes reset the count of consecutive failures
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, os.MkdirAll(tempDir, 0744))
+	list.Push(20)
+	require.Len(t, errs, 6)
+	_, err := os.Stat(filepath.Join(tempDir, "20"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,122 @@
+package concurrentList
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// slowBackend blocks every operation until it is released
+type slowBackend struct {
+	memoryBackend
+	release chan struct{}
+}
+
+func (b *slowBackend) Store(item interface{}) error {
+	<-b.release
+	return b.memoryBackend.Store(item)
+}
+
+func (b *slowBackend) Delete(item interface{}) error {
+	<-b.release
+	return b.memoryBackend.Delete(item)
+}
+
+// delayedBackend delays every operation
+type delayedBackend struct {
+	memoryBackend
+	delay time.Duration
+}
+
+func (b *delayedBackend) Store(item interface{}) error {
+	time.Sleep(b.delay)
+	return b.memoryBackend.Store(item)
+}
+
+func (b *delayedBackend) Delete(item interface{}) error {
+	time.Sleep(b.delay)
+	return b.memoryBackend.Delete(item)
+}
+
+func TestWithPersistenceTimeout(t *testing.T) {
+	backend := &slowBackend{release: make(chan struct{})}
+	list := NewConcurrentList(WithPersistenceBackend(backend), WithPersistenceTimeout(20*time.Millisecond))
+
+	// Push does not block past the timeout
+	started := time.Now()
+	list.Push(1)
+	require.True(t, time.Since(started) < 500*time.Millisecond)
+	require.False(t, list.PersistenceHealthy())
+
+	// While the write hangs, further operations are skipped right away instead of waiting for the timeout again
+	started = time.Now()
+	list.Push(2)
+	_, err := list.Shift()
+	require.NoError(t, err)
+	require.True(t, time.Since(started) < 20*time.Millisecond)
+	require.Equal(t, 1, list.Length())
+	errs := list.Errors()
+	require.Len(t, errs, 3)
+	for _, err := range errs {
+		require.ErrorIs(t, err, ErrPersistenceTimeout)
+	}
+
+	// The operation which timed out is still performed
+	close(backend.release)
+	require.NoError(t, list.FlushPersistence())
+	items, err := backend.Load()
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{1}, items)
+
+	list.Push(3)
+	require.True(t, list.PersistenceHealthy())
+	items, err = backend.Load()
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{1, 3}, items)
+}
+
+func TestWithPersistenceTimeoutRebuild(t *testing.T) {
+	type test struct {
+		Data string
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "TestWithPersistenceTimeoutRebuild")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	backend := &delayedBackend{delay: 20 * time.Millisecond}
+	list := NewConcurrentList(
+		WithContentHashPersistence(tempDir, test{}),
+		WithPersistenceBackend(backend),
+		WithPersistenceTimeout(5*time.Millisecond),
+	)
+
+	list.Push(test{Data: "removed"}, test{Data: "kept"}, test{Data: "kept"})
+	_, err := list.Shift()
+	require.NoError(t, err)
+
+	// Operations which timed out are done before the directory is rewritten, so none of them is performed afterwards
+	require.NoError(t, list.RebuildPersistence())
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	require.NoError(t, list.FlushPersistence())
+	files, err = ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	list.Push(test{Data: "kept"})
+	_, err = list.Shift()
+	require.NoError(t, err)
+	require.NoError(t, list.FlushPersistence())
+	files, err = ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
@@ -77,11 +77,12 @@ func TestWithPersistence(t *testing.T) {
 	}), WithSorting(func(i, j interface{}) bool {
 		return i.(test).Time.After(j.(test).Time)
 	}))
+	// The reconstructed list is sorted just like before
 	singleItem, err := list2.GetNext(context.Background())
 	require.NoError(t, err)
-	require.Equal(t, "fourthPush", singleItem.(test).Data)
+	require.Equal(t, "fifthPush", singleItem.(test).Data)
 
 	singleItem, err = list2.GetNext(context.Background())
 	require.NoError(t, err)
-	require.Equal(t, "fifthPush", singleItem.(test).Data)
+	require.Equal(t, "fourthPush", singleItem.(test).Data)
 }
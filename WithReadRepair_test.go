@@ -0,0 +1,63 @@
+package concurrentList
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithReadRepair(t *testing.T) {
+	type test struct {
+		ID   string
+		Data []string
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "TestWithReadRepair")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	// In the "old" format data was a comma-separated string
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "old"), []byte(`{"ID":"old","Data":"a,b"}`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "new"), []byte(`{"ID":"new","Data":["c"]}`), 0644))
+
+	repairCalls := 0
+	list, err := NewConcurrentListChecked(WithPersistence(tempDir, test{}, func(item interface{}) string {
+		return item.(test).ID
+	}), WithReadRepair(func(raw []byte) ([]byte, error) {
+		repairCalls++
+		old := struct {
+			ID   string
+			Data string
+		}{}
+		if err := json.Unmarshal(raw, &old); err != nil {
+			return nil, err
+		}
+		return json.Marshal(test{ID: old.ID, Data: strings.Split(old.Data, ",")})
+	}))
+	require.NoError(t, err)
+	require.Equal(t, 1, repairCalls)
+
+	items := list.GetWithFilter(func(item interface{}) bool { return item.(test).ID == "old" })
+	require.Equal(t, []interface{}{test{ID: "old", Data: []string{"a", "b"}}}, items)
+
+	// The repaired file is rewritten, so it does not need to be repaired again
+	rewritten, err := ioutil.ReadFile(filepath.Join(tempDir, "old"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"ID":"old","Data":["a","b"]}`, string(rewritten))
+
+	// Files which cannot be repaired fail the load
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "broken"), []byte(`{`), 0644))
+	_, err = NewConcurrentListChecked(WithPersistence(tempDir, test{}, func(item interface{}) string {
+		return item.(test).ID
+	}), WithReadRepair(func(raw []byte) ([]byte, error) {
+		return raw, nil
+	}))
+	require.Error(t, err)
+}
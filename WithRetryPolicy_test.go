@@ -0,0 +1,85 @@
+package concurrentList
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetryPolicy(t *testing.T) {
+	attempts := []int{}
+	list := NewConcurrentList(WithRetryPolicy(3, func(attempt int) time.Duration {
+		attempts = append(attempts, attempt)
+		return time.Duration(attempt) * 20 * time.Millisecond
+	}))
+	list.Push(1)
+
+	// Fails twice, then succeeds
+	failure := errors.New("failure")
+	calls := 0
+	started := time.Now()
+	for i := 0; i < 3; i++ {
+		err := list.ProcessNext(context.Background(), func(item interface{}) error {
+			calls++
+			if calls < 3 {
+				return failure
+			}
+			return nil
+		})
+		if i < 2 {
+			require.Equal(t, failure, err)
+			require.Equal(t, 1, list.Length())
+		} else {
+			require.NoError(t, err)
+		}
+	}
+	require.Equal(t, 3, calls)
+	require.Equal(t, []int{1, 2}, attempts)
+	require.True(t, time.Since(started) >= 60*time.Millisecond)
+	require.Equal(t, 0, list.Length())
+
+	// Items are skipped while they are waiting for their backoff
+	list.Push(2, 3)
+	require.Equal(t, failure, list.ProcessNext(context.Background(), func(item interface{}) error {
+		require.Equal(t, 2, item)
+		return failure
+	}))
+	require.NoError(t, list.ProcessNext(context.Background(), func(item interface{}) error {
+		require.Equal(t, 3, item)
+		return nil
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	require.Equal(t, ErrEmptyList, list.ProcessNext(ctx, func(item interface{}) error { return nil }))
+	require.Equal(t, 1, list.Length())
+}
+
+func TestWithRetryPolicyDeadLetter(t *testing.T) {
+	type deadLetter struct {
+		item interface{}
+		err  error
+	}
+	deadLetters := []deadLetter{}
+	list := NewConcurrentList(WithRetryPolicy(2, func(attempt int) time.Duration {
+		return time.Millisecond
+	}), WithDeadLetter(func(item interface{}, err error) {
+		deadLetters = append(deadLetters, deadLetter{item: item, err: err})
+	}))
+	list.Push(1)
+
+	failure := errors.New("failure")
+	calls := 0
+	for i := 0; i < 2; i++ {
+		require.Equal(t, failure, list.ProcessNext(context.Background(), func(item interface{}) error {
+			calls++
+			return failure
+		}))
+	}
+	require.Equal(t, 2, calls)
+	require.Equal(t, 0, list.Length())
+	require.Equal(t, []deadLetter{{item: 1, err: failure}}, deadLetters)
+	require.NoError(t, list.checkInvariants())
+}
@@ -0,0 +1,65 @@
+package concurrentList
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithShardedPersistence(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithShardedPersistence")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	shardFunc := func(item interface{}) string {
+		return fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprint(item))))[:1]
+	}
+	fileNameFunc := func(item interface{}) string {
+		return fmt.Sprint(item)
+	}
+
+	list := NewConcurrentList(WithShardedPersistence(tempDir, 0, shardFunc, fileNameFunc))
+	for i := 0; i < 100; i++ {
+		list.Push(i)
+	}
+	_, err := list.Shift()
+	require.NoError(t, err)
+	require.Empty(t, list.Errors())
+
+	// Items are spread across shards, no files are put directly into the rootPath
+	shards, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Greater(t, len(shards), 1)
+	totalFiles := 0
+	for _, shard := range shards {
+		require.True(t, shard.IsDir())
+		files, err := ioutil.ReadDir(filepath.Join(tempDir, shard.Name()))
+		require.NoError(t, err)
+		for _, file := range files {
+			require.Equal(t, shard.Name(), shardFunc(file.Name()))
+		}
+		totalFiles += len(files)
+	}
+	require.Equal(t, 99, totalFiles)
+
+	// Reconstruct from all shards
+	list, err = NewConcurrentListChecked(WithShardedPersistence(tempDir, 0, shardFunc, fileNameFunc))
+	require.NoError(t, err)
+	items := []int{}
+	for _, item := range list.GetWithFilter(func(item interface{}) bool { return true }) {
+		items = append(items, item.(int))
+	}
+	sort.Ints(items)
+	require.Len(t, items, 99)
+	for i, item := range items {
+		require.Equal(t, i+1, item)
+	}
+}
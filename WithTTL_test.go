@@ -0,0 +1,68 @@
+package concurrentList
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTTLKeepAlive(t *testing.T) {
+	type test struct {
+		ID      string
+		AddedAt time.Time
+	}
+
+	pinned := int32(1)
+	list := NewConcurrentList(WithTTL(50*time.Millisecond, 10*time.Millisecond, func(item interface{}) time.Time {
+		return item.(test).AddedAt
+	}, func(item interface{}) bool {
+		return item.(test).ID == "pinned" && atomic.LoadInt32(&pinned) == 1
+	}))
+
+	list.Push(test{ID: "pinned", AddedAt: time.Now()}, test{ID: "unpinned", AddedAt: time.Now()})
+
+	// The pinned item survives although it expired
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 1, list.Length())
+	item, err := list.Peek()
+	require.NoError(t, err)
+	require.Equal(t, "pinned", item.(test).ID)
+	require.Equal(t, int64(1), list.EvictedCount())
+
+	// It is removed by the next check once it is unpinned
+	atomic.StoreInt32(&pinned, 0)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, 0, list.Length())
+	require.Equal(t, int64(2), list.EvictedCount())
+}
+
+func TestWithTTLPrecision(t *testing.T) {
+	type test struct {
+		AddedAt time.Time
+	}
+
+	// The check interval is much longer than the ttl
+	expired := make(chan time.Time, 1)
+	list := NewConcurrentList(WithTTL(50*time.Millisecond, time.Hour, func(item interface{}) time.Time {
+		return item.(test).AddedAt
+	}), WithOnDiscard(func(item interface{}) {
+		expired <- time.Now()
+	}))
+	defer list.Close()
+
+	// Waits for items while the list is empty
+	time.Sleep(20 * time.Millisecond)
+	pushed := time.Now()
+	list.Push(test{AddedAt: pushed})
+
+	select {
+	case at := <-expired:
+		require.GreaterOrEqual(t, int64(at.Sub(pushed)), int64(50*time.Millisecond))
+		require.Less(t, int64(at.Sub(pushed)), int64(500*time.Millisecond))
+	case <-time.After(2 * time.Second):
+		t.Fatal("item did not expire close to its deadline")
+	}
+	require.Equal(t, 0, list.Length())
+}
@@ -0,0 +1,58 @@
+package concurrentList
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingTracer struct {
+	lock   sync.Mutex
+	starts int
+	waited []time.Duration
+	errs   []error
+}
+
+func (r *recordingTracer) OnGetNextStart(ctx context.Context) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.starts++
+}
+
+func (r *recordingTracer) OnGetNextEnd(ctx context.Context, waited time.Duration, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.waited = append(r.waited, waited)
+	r.errs = append(r.errs, err)
+}
+
+func TestWithTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	list := NewConcurrentList(WithTracer(tracer))
+
+	delay := 50 * time.Millisecond
+	go func() {
+		time.Sleep(delay)
+		list.Push(1)
+	}()
+
+	_, err := list.GetNext(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = list.GetNext(ctx)
+	require.Equal(t, ErrEmptyList, err)
+
+	tracer.lock.Lock()
+	defer tracer.lock.Unlock()
+	require.Equal(t, 2, tracer.starts)
+	require.Len(t, tracer.waited, 2)
+	require.GreaterOrEqual(t, int64(tracer.waited[0]), int64(delay))
+	require.Less(t, int64(tracer.waited[0]), int64(delay+40*time.Millisecond))
+	require.NoError(t, tracer.errs[0])
+	require.Equal(t, ErrEmptyList, tracer.errs[1])
+}
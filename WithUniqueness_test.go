@@ -0,0 +1,70 @@
+package concurrentList
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type uniquenessTest struct {
+	Key     string
+	Version int
+}
+
+func TestWithUniqueness(t *testing.T) {
+	list := NewConcurrentList(WithUniqueness(func(item interface{}) string {
+		return item.(uniquenessTest).Key
+	}))
+
+	list.Push(uniquenessTest{Key: "a", Version: 1}, uniquenessTest{Key: "b", Version: 1}, uniquenessTest{Key: "a", Version: 2})
+	list.Push(uniquenessTest{Key: "b", Version: 2})
+	require.Equal(t, []interface{}{uniquenessTest{Key: "a", Version: 1}, uniquenessTest{Key: "b", Version: 1}}, list.GetWithFilter(func(item interface{}) bool { return true }))
+
+	// Skipped items are accepted
+	accepted, err := list.TryPush(uniquenessTest{Key: "a", Version: 3}, uniquenessTest{Key: "c", Version: 1})
+	require.NoError(t, err)
+	require.Equal(t, 2, accepted)
+	require.Equal(t, 3, list.Length())
+
+	// The key is free again once the item left the list
+	_, err = list.Shift()
+	require.NoError(t, err)
+	list.Push(uniquenessTest{Key: "a", Version: 4})
+	require.Equal(t, 3, list.Length())
+}
+
+func TestWithUniquenessReload(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithUniquenessReload")
+	_ = os.MkdirAll(tempDir, 0744)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	// Two files hold an item with the same key
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "a-1"), []byte(`{"Key":"a","Version":1}`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "a-2"), []byte(`{"Key":"a","Version":2}`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "b-1"), []byte(`{"Key":"b","Version":1}`), 0644))
+
+	// The item which is sorted first is kept
+	list, err := NewConcurrentListChecked(WithPersistence(tempDir, uniquenessTest{}, func(item interface{}) string {
+		return fmt.Sprintf("%s-%d", item.(uniquenessTest).Key, item.(uniquenessTest).Version)
+	}), WithSorting(func(i, j interface{}) bool {
+		return i.(uniquenessTest).Version > j.(uniquenessTest).Version
+	}), WithUniqueness(func(item interface{}) string {
+		return item.(uniquenessTest).Key
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{uniquenessTest{Key: "a", Version: 2}, uniquenessTest{Key: "b", Version: 1}}, list.GetWithFilter(func(item interface{}) bool { return true }))
+	require.Empty(t, list.Errors())
+
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	_, err = os.Stat(filepath.Join(tempDir, "a-1"))
+	require.True(t, os.IsNotExist(err))
+	require.NoError(t, list.checkInvariants())
+}
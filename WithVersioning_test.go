@@ -0,0 +1,112 @@
+package concurrentList
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithVersioning(t *testing.T) {
+	type test struct {
+		Name string
+		Data string
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "TestWithVersioning")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	opts := []ConcurrentListOption{
+		WithPersistence(tempDir, test{}, func(item interface{}) string {
+			return item.(test).Name
+		}),
+		WithVersioning(),
+	}
+	version := func() uint64 {
+		contents, err := ioutil.ReadFile(filepath.Join(tempDir, "shared"))
+		require.NoError(t, err)
+		record := persistedRecord{}
+		require.NoError(t, json.Unmarshal(contents, &record))
+		return record.Version
+	}
+
+	// Two lists share a directory, the later writer supersedes the earlier one
+	first := NewConcurrentList(opts...)
+	first.Push(test{Name: "shared", Data: "first"})
+	require.Equal(t, uint64(1), version())
+
+	second := NewConcurrentList(opts...)
+	require.Equal(t, 1, second.Length())
+	second.Push(test{Name: "shared", Data: "second"})
+	require.Equal(t, uint64(2), version())
+
+	reloaded := NewConcurrentList(opts...)
+	require.Equal(t, []interface{}{test{Name: "shared", Data: "second"}}, reloaded.Snapshot())
+
+	// The first list does not delete the file which was written by the second one
+	_, err := first.Shift()
+	require.NoError(t, err)
+	errs := first.Errors()
+	require.Len(t, errs, 1)
+	require.True(t, errors.Is(errs[0], ErrVersionConflict))
+	require.Equal(t, uint64(2), version())
+
+	// The second list knows the latest version
+	_, err = second.Shift()
+	require.NoError(t, err)
+	require.Empty(t, second.Errors())
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 0)
+}
+
+func TestWithVersioningUnversionedFiles(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithVersioningUnversionedFiles")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	persistence := WithPersistence(tempDir, 0, func(item interface{}) string {
+		return "item"
+	})
+	NewConcurrentList(persistence).Push(1)
+
+	// Files which were written before versioning was turned on are read
+	list := NewConcurrentList(persistence, WithVersioning())
+	require.Equal(t, []interface{}{1}, list.Snapshot())
+	require.Empty(t, list.Errors())
+}
+
+func TestWithVersioningAsync(t *testing.T) {
+	tempDir := filepath.Join(os.TempDir(), "TestWithVersioningAsync")
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	}()
+
+	// Versions are tracked by the worker and by operations which exceed the timeout, while the list keeps changing
+	list := NewConcurrentList(WithPersistence(tempDir, 0, func(item interface{}) string {
+		return fmt.Sprint(item)
+	}), WithVersioning(), WithAsyncPersistence(1000), WithPersistenceTimeout(time.Microsecond))
+	for i := 0; i < 100; i++ {
+		list.Push(i)
+		if i%3 == 0 {
+			_, err := list.Shift()
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, list.FlushPersistence())
+	require.NoError(t, list.RebuildPersistence())
+
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, list.Length())
+	require.NoError(t, list.Close())
+}
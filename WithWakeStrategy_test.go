@@ -0,0 +1,68 @@
+package concurrentList
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithWakeStrategy(t *testing.T) {
+	for name, strategy := range map[string]WakeStrategy{"signal": WakeSignal, "broadcast": WakeBroadcast} {
+		t.Run(name, func(t *testing.T) {
+			list := NewConcurrentList(WithWakeStrategy(strategy))
+
+			consumers := 50
+			items := 5000
+			consumed := make([]int, items)
+			consumedLock := sync.Mutex{}
+
+			wg := sync.WaitGroup{}
+			for i := 0; i < consumers; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					for {
+						// Some consumers give up early and retry, so wake-ups are passed on
+						timeout := time.Second
+						if i%2 == 0 {
+							timeout = time.Millisecond
+						}
+						ctx, cancel := context.WithTimeout(context.Background(), timeout)
+						item, err := list.GetNext(ctx)
+						cancel()
+						if err == ErrClosed {
+							return
+						}
+						if err != nil {
+							continue
+						}
+						consumedLock.Lock()
+						consumed[item.(int)]++
+						consumedLock.Unlock()
+					}
+				}(i)
+			}
+
+			for i := 0; i < items; i++ {
+				list.Push(i)
+			}
+
+			require.Eventually(t, func() bool { return list.Length() == 0 }, 10*time.Second, time.Millisecond)
+			list.Close()
+			wg.Wait()
+
+			for i := 0; i < items; i++ {
+				require.Equal(t, 1, consumed[i], "item %d", i)
+			}
+		})
+	}
+}
+
+func TestWakeStrategyDefault(t *testing.T) {
+	// The safe strategy is the default
+	require.Equal(t, WakeBroadcast, NewConcurrentList().opts.wakeStrategy)
+	require.Equal(t, WakeSignal, NewConcurrentList(WithWakeStrategy(WakeSignal)).opts.wakeStrategy)
+}